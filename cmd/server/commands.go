@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/mcp"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/server"
+)
+
+// command runs a service handler from the command line instead of serving
+// MCP. Its flags are turned into the handler's arguments, and it runs
+// through the same audit and policy as the tool it stands for.
+type command struct {
+	usage string
+	// tool names the command in the audit log and the policy
+	tool     string
+	mutating bool
+	// confirm adds --yes, which confirms deletions up front instead of
+	// only previewing them
+	confirm bool
+	// flags defines the command's flags and returns a function that
+	// builds the handler arguments after parsing
+	flags func(fs *flag.FlagSet) func() map[string]interface{}
+	run   func(*azubiheftserver.AzubiheftService, context.Context, map[string]interface{}) (string, error)
+}
+
+var commands = map[string]command{
+	"export": {
+		usage: "Write every week, day and subject of the account to a JSON archive",
+		tool:  "azubiheft_export_backup",
+		flags: func(fs *flag.FlagSet) func() map[string]interface{} {
+			path := fs.String("path", "azubiheft-backup.json", "Archive file to write")
+			return func() map[string]interface{} {
+				return stringArgs(map[string]string{"path": *path}, nil)
+			}
+		},
+		run: (*azubiheftserver.AzubiheftService).ExportBackup,
+	},
+	"import": {
+		usage:    "Replay a JSON archive written by export into the account",
+		tool:     "azubiheft_import_backup",
+		mutating: true,
+		flags: func(fs *flag.FlagSet) func() map[string]interface{} {
+			path := fs.String("path", "azubiheft-backup.json", "Archive file to read")
+			from := fs.String("from", "", "Only import days from this date (YYYY-MM-DD)")
+			to := fs.String("to", "", "Only import days up to this date (YYYY-MM-DD)")
+			dryRun := fs.Bool("dry-run", false, "Only report what would change")
+			return func() map[string]interface{} {
+				return stringArgs(map[string]string{
+					"path": *path, "from": *from, "to": *to,
+				}, map[string]bool{"dry_run": *dryRun})
+			}
+		},
+		run: (*azubiheftserver.AzubiheftService).ImportBackup,
+	},
+	"report export": {
+		usage: "Export the entries of a date range as Markdown, CSV, JSON, PDF or DOCX",
+		tool:  "azubiheft_export_report",
+		flags: func(fs *flag.FlagSet) func() map[string]interface{} {
+			from := fs.String("from", "", "First date (YYYY-MM-DD)")
+			to := fs.String("to", "", "Last date (YYYY-MM-DD)")
+			weekOf := fs.String("week-of", "", "Any date in the week to export, alternative to --from/--to")
+			format := fs.String("format", "", "md, csv, json, pdf or docx (default: from the --path extension, otherwise md)")
+			path := fs.String("path", "", "File to write (default: standard output)")
+			groupByWeek := fs.Bool("group-by-week", true, "Group the days by calendar week")
+			totals := fs.Bool("totals", true, "Add the time spent per entry type")
+			formatting := fs.Bool("formatting", false, "Keep the formatting of entry texts as Markdown")
+			return func() map[string]interface{} {
+				args := stringArgs(map[string]string{
+					"from": *from, "to": *to, "week_of": *weekOf, "format": *format, "path": *path,
+				}, map[string]bool{"include_formatting": *formatting})
+				args["group_by_week"] = *groupByWeek
+				args["totals"] = *totals
+				return args
+			}
+		},
+		run: (*azubiheftserver.AzubiheftService).ExportReport,
+	},
+	"site": {
+		usage: "Render all reports into a static HTML site",
+		tool:  "azubiheft_build_site",
+		flags: func(fs *flag.FlagSet) func() map[string]interface{} {
+			dir := fs.String("dir", "site", "Folder to write the site to")
+			from := fs.String("from", "", "Only include weeks from this date (YYYY-MM-DD)")
+			to := fs.String("to", "", "Only include weeks up to this date (YYYY-MM-DD)")
+			title := fs.String("title", "", "Site title (default: Berichtsheft)")
+			return func() map[string]interface{} {
+				return stringArgs(map[string]string{"dir": *dir, "from": *from, "to": *to, "title": *title}, nil)
+			}
+		},
+		run: (*azubiheftserver.AzubiheftService).BuildSite,
+	},
+	"sync": {
+		usage:    "Sync a folder of daily Markdown notes with the report",
+		tool:     "azubiheft_sync_notes",
+		mutating: true,
+		confirm:  true,
+		flags: func(fs *flag.FlagSet) func() map[string]interface{} {
+			dir := fs.String("dir", "", "Notes folder (default: notes_dir from the config)")
+			from := fs.String("from", "", "First date (YYYY-MM-DD)")
+			to := fs.String("to", "", "Last date (YYYY-MM-DD)")
+			weekOf := fs.String("week-of", "", "Any date in the week to sync, alternative to --from/--to")
+			direction := fs.String("direction", "both", "both, push or pull")
+			prefer := fs.String("prefer", "", "Resolve conflicts with the local or remote version")
+			dryRun := fs.Bool("dry-run", false, "Only report what would change")
+			return func() map[string]interface{} {
+				return stringArgs(map[string]string{
+					"dir": *dir, "from": *from, "to": *to, "week_of": *weekOf,
+					"direction": *direction, "prefer": *prefer,
+				}, map[string]bool{"dry_run": *dryRun})
+			}
+		},
+		run: (*azubiheftserver.AzubiheftService).SyncNotes,
+	},
+}
+
+// runCommand runs the named command with its command line arguments and
+// returns the exit code. A command named by two words, like "report
+// export", takes the second word from args. The middlewares of srv, such as
+// the audit log and the policy, apply as for tool calls.
+func runCommand(name string, args []string, srv *mcp.Server, service *azubiheftserver.AzubiheftService, stdout, stderr io.Writer) int {
+	cmd, ok := commands[name]
+	if !ok && len(args) > 0 {
+		if cmd, ok = commands[name+" "+args[0]]; ok {
+			name, args = name+" "+args[0], args[1:]
+		}
+	}
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command %q\n\n", name)
+		printCommands(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s [global flags] %s [flags]\n\n%s\n\n", os.Args[0], name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	toolArgs := cmd.flags(fs)
+	yes := new(bool)
+	if cmd.confirm {
+		yes = fs.Bool("yes", false, "Delete entries that need a confirmation without showing a preview first")
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if service.GetDefaultSessionID() == "" {
+		fmt.Fprintln(stderr, "Error: commands need AZUBIHEFT_USERNAME and AZUBIHEFT_PASSWORD to log in")
+		return 1
+	}
+
+	handler := srv.Handle(cmd.tool, cmd.mutating, func(ctx context.Context, args map[string]interface{}) (string, error) {
+		return cmd.run(service, ctx, args)
+	})
+	ctx := azubiheftserver.CommandLine(context.Background(), *yes)
+	result, err := handler(ctx, toolArgs())
+	service.Close()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, strings.TrimRight(result, "\n"))
+	return 0
+}
+
+func printCommands(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", name, commands[name].usage)
+	}
+}
+
+// stringArgs builds handler arguments, leaving out empty strings and
+// false flags like an MCP client omits unset properties
+func stringArgs(values map[string]string, flags map[string]bool) map[string]interface{} {
+	args := make(map[string]interface{})
+	for key, value := range values {
+		if value != "" {
+			args[key] = value
+		}
+	}
+	for key, value := range flags {
+		if value {
+			args[key] = value
+		}
+	}
+	return args
+}
@@ -1,16 +1,41 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/audit"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/cache"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
 	"github.com/konrad-maedler/azubiheft-mcp-server/internal/mcp"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
 	"github.com/konrad-maedler/azubiheft-mcp-server/internal/server"
 )
 
 func main() {
 	logger := log.New(os.Stderr, "[azubiheft-mcp] ", log.LstdFlags)
 
+	dataDir := flag.String("data-dir", defaultDataDir(), "Directory for local state such as report drafts")
+	dryRun := flag.Bool("dry-run", false, "Report the requests mutating tools would send instead of sending them")
+	readOnly := flag.Bool("read-only", false, "Disable every tool that changes the Azubiheft account")
+	policyPath := flag.String("policy", "", "Tool policy file (default: policy.json in the data directory, if present)")
+	configPath := flag.String("config", "", "Config file (default: config.json in the data directory, if present)")
+	baseURL := flag.String("base-url", os.Getenv("AZUBIHEFT_BASE_URL"), "Address of the Azubiheft site (default: "+azubiheft.DefaultBaseURL+")")
+	cacheTTL := flag.Duration("cache-ttl", 5*time.Minute, "How long reads are served from the local report cache (0 disables the cache)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command [command flags]]\n\nWithout a command, the MCP server is started on stdin/stdout.\n\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output())
+		printCommands(flag.CommandLine.Output())
+	}
+	flag.Parse()
+
 	username := os.Getenv("AZUBIHEFT_USERNAME")
 	password := os.Getenv("AZUBIHEFT_PASSWORD")
 
@@ -20,16 +45,95 @@ func main() {
 		logger.Println("No credentials in environment - manual login required")
 	}
 
+	cfg, err := loadConfig(*configPath, *dataDir)
+	if err != nil {
+		logger.Fatalf("Config error: %v", err)
+	}
+
+	auditLog := audit.New(filepath.Join(*dataDir, "audit.jsonl"), audit.DefaultMaxSize, audit.DefaultMaxFiles)
+
 	mcpServer := mcp.NewServer("Azubiheft MCP Server", "1.0.0", logger)
-	azubiheftService := azubiheftserver.NewAzubiheftService(logger, username, password)
+	mcpServer.Use(auditLog.Middleware)
+	mcpServer.SetReadOnly(*readOnly)
+
+	toolPolicy, err := loadPolicy(*policyPath, *dataDir)
+	if err != nil {
+		logger.Fatalf("Policy error: %v", err)
+	}
+	if toolPolicy != nil {
+		logger.Println("Tool policy loaded")
+		mcpServer.Use(policy.NewEngine(*toolPolicy, mcpServer.IsMutating).Middleware)
+	}
+	var reportCache *cache.Store
+	if *cacheTTL > 0 {
+		reportCache = cache.Open(filepath.Join(*dataDir, "cache.json"), *cacheTTL)
+	}
+
+	azubiheftService := azubiheftserver.NewAzubiheftService(logger, username, password, azubiheftserver.Options{
+		DataDir:  *dataDir,
+		DryRun:   *dryRun,
+		Audit:    auditLog,
+		ReadOnly: *readOnly,
+		Config:   cfg,
+		BaseURL:  *baseURL,
+		Cache:    reportCache,
+	})
+
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Arg(0), flag.Args()[1:], mcpServer, azubiheftService, os.Stdout, os.Stderr))
+	}
+
+	if *dryRun {
+		logger.Println("Dry-run mode: mutating tools will not change the account")
+	}
+	if *readOnly {
+		logger.Println("Read-only mode: mutating tools are disabled")
+	}
 	registerTools(mcpServer, azubiheftService)
 
 	logger.Println("Starting Azubiheft MCP Server...")
-	if err := mcpServer.Serve(); err != nil {
+	err = mcpServer.Serve()
+	azubiheftService.Close()
+	if err != nil {
 		logger.Fatalf("Server error: %v", err)
 	}
 }
 
+// defaultDataDir returns AZUBIHEFT_DATA_DIR or a directory below the user's
+// config directory
+func defaultDataDir() string {
+	if dir := os.Getenv("AZUBIHEFT_DATA_DIR"); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "azubiheft-mcp")
+	}
+	return ".azubiheft-mcp"
+}
+
+// loadConfig reads the config file given by --config, or config.json from
+// the data directory if it exists
+func loadConfig(path, dataDir string) (*config.Config, error) {
+	if path != "" {
+		return config.Load(path, false)
+	}
+	return config.Load(filepath.Join(dataDir, "config.json"), true)
+}
+
+// loadPolicy reads the policy file given by --policy, or policy.json from
+// the data directory if it exists. It returns nil if no policy applies.
+func loadPolicy(path, dataDir string) (*policy.Policy, error) {
+	if path != "" {
+		return policy.Load(path)
+	}
+
+	path = filepath.Join(dataDir, "policy.json")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return policy.Load(path)
+}
+
 func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 	s.RegisterTool(
 		"azubiheft_login",
@@ -98,7 +202,7 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 		service.GetSubjects,
 	)
 
-	s.RegisterTool(
+	s.RegisterMutatingTool(
 		"azubiheft_add_subject",
 		"Adds a new custom subject to the user's subject list",
 		map[string]interface{}{
@@ -112,15 +216,19 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 					"type":        "string",
 					"description": "Name of the new subject",
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
 			},
 			"required": []string{"session_id", "subject_name"},
 		},
 		service.AddSubject,
 	)
 
-	s.RegisterTool(
+	s.RegisterMutatingTool(
 		"azubiheft_delete_subject",
-		"Removes a subject from the user's subject list",
+		"Removes a subject from the user's subject list. The first call returns a preview and a confirmation token; the subject is only deleted when called again with that token.",
 		map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -132,6 +240,14 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 					"type":        "string",
 					"description": "ID of the subject to delete",
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+				"confirmation_token": map[string]interface{}{
+					"type":        "string",
+					"description": "Token from the preview returned by the first call. Omit it to get a preview.",
+				},
 			},
 			"required": []string{"session_id", "subject_id"},
 		},
@@ -154,7 +270,7 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 				},
 				"include_formatting": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Whether to include HTML formatting (default: false)",
+					"description": "Whether to return the text as Markdown (bold, italic, lists, line breaks) instead of plain text (default: false)",
 				},
 			},
 			"required": []string{"session_id", "date"},
@@ -162,7 +278,7 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 		service.GetReport,
 	)
 
-	s.RegisterTool(
+	s.RegisterMutatingTool(
 		"azubiheft_write_report",
 		"Writes a single report entry for a specific date",
 		map[string]interface{}{
@@ -178,7 +294,7 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 				},
 				"message": map[string]interface{}{
 					"type":        "string",
-					"description": "Content of the report",
+					"description": "Content of the report. Supports Markdown: **bold**, *italic*, '- ' bullet lists, '1. ' numbered lists and line breaks",
 				},
 				"time_spent": map[string]interface{}{
 					"type":        "string",
@@ -188,15 +304,19 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 					"type":        "number",
 					"description": "Subject ID (1-7 for static, higher for user-defined)",
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
 			},
 			"required": []string{"session_id", "date", "message", "time_spent", "entry_type"},
 		},
 		service.WriteReport,
 	)
 
-	s.RegisterTool(
+	s.RegisterMutatingTool(
 		"azubiheft_delete_report",
-		"Deletes one or all report entries for a specific date",
+		"Deletes one or all report entries for a specific date. The first call returns a preview of the affected entries and a confirmation token; the entries are only deleted when called again with that token.",
 		map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -212,6 +332,14 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 					"type":        "number",
 					"description": "Entry number to delete (omit to delete all)",
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+				"confirmation_token": map[string]interface{}{
+					"type":        "string",
+					"description": "Token from the preview returned by the first call. Omit it to get a preview.",
+				},
 			},
 			"required": []string{"session_id", "date"},
 		},
@@ -237,4 +365,755 @@ func registerTools(s *mcp.Server, service *azubiheftserver.AzubiheftService) {
 		},
 		service.GetWeekID,
 	)
+
+	s.RegisterTool(
+		"azubiheft_create_draft",
+		"Stores a report entry as a local draft for review. Nothing is sent to Azubiheft until the draft is published.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"date": map[string]interface{}{
+					"type":        "string",
+					"description": "Date in YYYY-MM-DD format",
+				},
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "Content of the report (Markdown supported)",
+				},
+				"time_spent": map[string]interface{}{
+					"type":        "string",
+					"description": "Duration in HH:MM format (must not be 00:00)",
+				},
+				"entry_type": map[string]interface{}{
+					"type":        "number",
+					"description": "Subject ID (1-7 for static, higher for user-defined)",
+				},
+			},
+			"required": []string{"date", "message", "time_spent", "entry_type"},
+		},
+		service.CreateDraft,
+	)
+
+	s.RegisterTool(
+		"azubiheft_list_drafts",
+		"Lists local report drafts, optionally limited to a date range",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format (optional)",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format (optional)",
+				},
+			},
+		},
+		service.ListDrafts,
+	)
+
+	s.RegisterTool(
+		"azubiheft_edit_draft",
+		"Changes fields of a local report draft. Omitted fields are left unchanged.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"draft_id": map[string]interface{}{
+					"type":        "string",
+					"description": "ID of the draft to edit",
+				},
+				"date": map[string]interface{}{
+					"type":        "string",
+					"description": "New date in YYYY-MM-DD format",
+				},
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "New content of the report (Markdown supported)",
+				},
+				"time_spent": map[string]interface{}{
+					"type":        "string",
+					"description": "New duration in HH:MM format",
+				},
+				"entry_type": map[string]interface{}{
+					"type":        "number",
+					"description": "New subject ID",
+				},
+			},
+			"required": []string{"draft_id"},
+		},
+		service.EditDraft,
+	)
+
+	s.RegisterTool(
+		"azubiheft_discard_draft",
+		"Deletes local report drafts without publishing them",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"draft_ids": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "IDs of the drafts to discard",
+				},
+			},
+			"required": []string{"draft_ids"},
+		},
+		service.DiscardDraft,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_publish_drafts",
+		"Writes the selected drafts to Azubiheft and removes them from the local store. Select drafts by ID or by date range.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"draft_ids": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "IDs of the drafts to publish",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "Publish all drafts from this date (YYYY-MM-DD) if draft_ids is omitted",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Publish all drafts up to this date (YYYY-MM-DD) if draft_ids is omitted",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+		},
+		service.PublishDrafts,
+	)
+
+	s.RegisterTool(
+		"azubiheft_list_journal",
+		"Lists recent report mutations recorded in the local undo journal",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of entries to show (default: 20)",
+				},
+			},
+		},
+		service.ListJournal,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_undo",
+		"Restores the entries of the days changed by the last N operations, or by a specific journal entry",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"count": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of most recent operations to undo (default: 1)",
+				},
+				"journal_id": map[string]interface{}{
+					"type":        "number",
+					"description": "ID of a specific journal entry to undo (overrides count)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+		},
+		service.Undo,
+	)
+
+	s.RegisterTool(
+		"azubiheft_audit",
+		"Queries the local audit log of tool calls and the upstream changes they caused",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First day in YYYY-MM-DD format (optional)",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last day in YYYY-MM-DD format (optional)",
+				},
+				"tool": map[string]interface{}{
+					"type":        "string",
+					"description": "Only show calls of this tool (optional)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of entries, newest kept (default: 50)",
+				},
+			},
+		},
+		service.QueryAudit,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_write_range",
+		"Writes the same entries on every selected weekday of a date range. Weekends and configured days off are skipped, entries that already exist are not written twice, and the result lists the outcome per day so failed days can be retried with the same call.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"weekdays": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Weekdays to write, e.g. [\"monday\", \"friday\"] (default: Monday to Friday)",
+				},
+				"entries": map[string]interface{}{
+					"type":        "array",
+					"description": "Entries to write on each selected day",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"message": map[string]interface{}{
+								"type":        "string",
+								"description": "Content of the report (Markdown supported)",
+							},
+							"time_spent": map[string]interface{}{
+								"type":        "string",
+								"description": "Duration in HH:MM format",
+							},
+							"entry_type": map[string]interface{}{
+								"type":        "number",
+								"description": "Subject ID",
+							},
+						},
+						"required": []string{"message", "time_spent", "entry_type"},
+					},
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+			"required": []string{"from", "to", "entries"},
+		},
+		service.WriteRange,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_copy_week",
+		"Copies all entries of one week to the same weekdays of another week. With mode overwrite, existing entries that would be deleted are first shown in a preview that must be confirmed with its token.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"source_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to copy from (YYYY-MM-DD)",
+				},
+				"target_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to copy to (YYYY-MM-DD)",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"overwrite", "append", "skip"},
+					"description": "What to do with target days that already have entries: replace them, add to them, or leave them alone (default: skip)",
+				},
+				"structure_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only copy entry types and durations and use placeholder text (default: false)",
+				},
+				"placeholder": map[string]interface{}{
+					"type":        "string",
+					"description": "Text for copied entries when structure_only is set (default: TODO)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+				"confirmation_token": map[string]interface{}{
+					"type":        "string",
+					"description": "Token from the preview returned when overwrite would delete entries. Omit it to get a preview.",
+				},
+			},
+			"required": []string{"source_date", "target_date"},
+		},
+		service.CopyWeek,
+	)
+
+	s.RegisterOptionallyMutatingTool(
+		"azubiheft_apply_template",
+		"Creates the entries of a configured weekday template for a week or date range, as drafts (default) or directly in the report. Days that already have drafts or entries are skipped.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"template": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the template (optional if only one is configured)",
+				},
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to fill (YYYY-MM-DD), alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"target": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"drafts", "report"},
+					"description": "Create local drafts or write to Azubiheft directly (default: drafts)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+		},
+		service.ApplyTemplate,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_fill_holidays",
+		"Writes Feiertag entries for all public holidays on workdays of a year or date range. Days that already have entries are skipped.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"year": map[string]interface{}{
+					"type":        "number",
+					"description": "Year to fill, alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"state": map[string]interface{}{
+					"type":        "string",
+					"description": "Bundesland code such as NW or BY (default: state from the config)",
+				},
+				"time_spent": map[string]interface{}{
+					"type":        "string",
+					"description": "Duration in HH:MM format (default: daily_hours from the config, or 08:00)",
+				},
+				"target": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"drafts", "report"},
+					"description": "Create local drafts or write to Azubiheft directly (default: report)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+		},
+		service.FillHolidays,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_mark_absence",
+		"Writes an Urlaub or Arbeitsunfähig entry with the standard daily hours on every workday of a date range. Weekends, public holidays and configured days off are skipped. Days with other entries are left alone unless overwrite is set; entries it would replace are first shown in a preview that must be confirmed with its token.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First day of the absence in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last day of the absence in YYYY-MM-DD format",
+				},
+				"absence_type": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"urlaub", "arbeitsunfaehig"},
+					"description": "Vacation (Urlaub) or sick leave (Arbeitsunfähig)",
+				},
+				"time_spent": map[string]interface{}{
+					"type":        "string",
+					"description": "Duration per day in HH:MM format (default: daily_hours from the config, or 08:00)",
+				},
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "Entry text (default: the absence type)",
+				},
+				"overwrite": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Replace existing entries on the affected days (default: false)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+				"confirmation_token": map[string]interface{}{
+					"type":        "string",
+					"description": "Token from the preview returned when overwrite would delete entries. Omit it to get a preview.",
+				},
+			},
+			"required": []string{"from", "to", "absence_type"},
+		},
+		service.MarkAbsence,
+	)
+
+	s.RegisterTool(
+		"azubiheft_vacation_balance",
+		"Computes taken, planned and remaining vacation days of a training year from the Urlaub entries and drafts, and warns if the plan exceeds the configured entitlement",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"date": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the training year to check (YYYY-MM-DD, default: today)",
+				},
+				"include_drafts": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Count Urlaub drafts as planned vacation (default: true)",
+				},
+			},
+		},
+		service.VacationBalance,
+	)
+
+	s.RegisterOptionallyMutatingTool(
+		"azubiheft_import_calendar",
+		"Imports events of a local .ics calendar file for a week or date range. Events are mapped to entry types by the calendar_rules of the config, durations come from the event times, and the entries of each day are combined per type. Creates drafts (default) or writes to the report; days that already have drafts or entries are skipped.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path of the .ics file",
+				},
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to import (YYYY-MM-DD), alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"default_entry_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Entry type for events no calendar rule matches (default: such events are not imported)",
+				},
+				"target": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"drafts", "report"},
+					"description": "Create local drafts or write to Azubiheft directly (default: drafts)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+			"required": []string{"path"},
+		},
+		service.ImportCalendar,
+	)
+
+	s.RegisterTool(
+		"azubiheft_drafts_from_git",
+		"Creates report drafts from the commits of local git repositories for a week or date range. Commits are grouped per day, their subjects cleaned of conventional-commit prefixes and the time estimated from the commit times. Only local drafts are created, nothing is written to Azubiheft; days that already have drafts are skipped.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"repositories": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Paths of local repositories (default: git.repositories from the config)",
+				},
+				"authors": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Author names or emails (default: git.authors from the config)",
+				},
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week (YYYY-MM-DD), alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"entry_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Entry type of the drafts (default: 1, Betrieb)",
+				},
+			},
+		},
+		service.DraftsFromGit,
+	)
+
+	s.RegisterOptionallyMutatingTool(
+		"azubiheft_import_timesheet",
+		"Imports a CSV export of a time-tracking tool (Toggl, Clockify, Excel). Rows are mapped to entry types by the time_tracking rules of the config and summed per day and type. Shows a preview by default; use target drafts or report to import. Lines with invalid values block the import unless ignore_errors is set.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path of the CSV file",
+				},
+				"preset": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"toggl", "clockify", "excel"},
+					"description": "Column layout of the export (default: time_tracking.preset from the config)",
+				},
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Only import rows of the week containing this date (YYYY-MM-DD)",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "Only import rows from this date (YYYY-MM-DD)",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Only import rows up to this date (YYYY-MM-DD)",
+				},
+				"default_entry_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Entry type for rows no rule matches (default: such rows are not imported)",
+				},
+				"target": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"preview", "drafts", "report"},
+					"description": "Only preview, create local drafts or write to Azubiheft directly (default: preview)",
+				},
+				"ignore_errors": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Import the valid rows even if some lines are invalid (default: false)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+			"required": []string{"path"},
+		},
+		service.ImportTimesheet,
+	)
+
+	s.RegisterMutatingTool(
+		"azubiheft_sync_notes",
+		"Syncs a folder of daily Markdown notes (YYYY-MM-DD.md) with the report for a week or date range. Notes changed since the last sync replace the entries of their day, entries changed on Azubiheft are written back to the notes, and days changed on both sides are reported as conflicts.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"dir": map[string]interface{}{
+					"type":        "string",
+					"description": "Notes folder (default: notes_dir from the config)",
+				},
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to sync (YYYY-MM-DD), alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"direction": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"both", "push", "pull"},
+					"description": "Only push notes to Azubiheft, only pull entries into notes, or both (default: both)",
+				},
+				"prefer": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"local", "remote"},
+					"description": "Resolve conflicts with the note (local) or the report (remote) instead of reporting them",
+				},
+				"confirmation_token": map[string]interface{}{
+					"type":        "string",
+					"description": "Token from the preview returned when prefer local would delete entries changed on Azubiheft. Omit it to get a preview.",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the requests that would be sent (default: false)",
+				},
+			},
+		},
+		service.SyncNotes,
+	)
+
+	s.RegisterTool(
+		"azubiheft_export_report",
+		"Exports the entries of a week or date range as Markdown, CSV or JSON, grouped by calendar week with the time spent per entry type, or as a print-ready PDF or Word (DOCX) Ausbildungsnachweis with a page per week and signature lines. Writes the export to a local file, or returns it if no path is given.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to export (YYYY-MM-DD), alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "File to write the export to",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"md", "csv", "json", "pdf", "docx"},
+					"description": "Export format (default: from the file extension of path, otherwise md)",
+				},
+				"group_by_week": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Group the days by calendar week (default: true)",
+				},
+				"totals": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Add the time spent per entry type (default: true)",
+				},
+				"include_formatting": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the formatting of entry texts as Markdown (default: false)",
+				},
+			},
+		},
+		service.ExportReport,
+	)
+
+	s.RegisterTool(
+		"azubiheft_build_site",
+		"Renders all reports into a self-contained static HTML site in a local folder: a dashboard with hours per entry type and month, a week index with a page per week, a page per subject, and a search page backed by a prebuilt index. The folder must be empty or hold a site built before.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID from login (optional if using auto-login)",
+				},
+				"dir": map[string]interface{}{
+					"type":        "string",
+					"description": "Folder to write the site to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "Only include weeks from this date (YYYY-MM-DD)",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Only include weeks up to this date (YYYY-MM-DD)",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Site title (default: Berichtsheft)",
+				},
+			},
+			"required": []string{"dir"},
+		},
+		service.BuildSite,
+	)
+
+	s.RegisterTool(
+		"azubiheft_cache_status",
+		"Shows the local report cache: how many weeks, days and subjects are cached, how many are still fresh, and hits and misses since the server started",
+		map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		service.CacheStatus,
+	)
+
+	s.RegisterTool(
+		"azubiheft_clear_cache",
+		"Drops cached reports so that the next reads go to Azubiheft, for example after editing reports on the website. Without a date range the whole cache is cleared.",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"week_of": map[string]interface{}{
+					"type":        "string",
+					"description": "Any date in the week to clear (YYYY-MM-DD), alternative to from/to",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "First date in YYYY-MM-DD format (optional)",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Last date in YYYY-MM-DD format (optional)",
+				},
+			},
+		},
+		service.ClearCache,
+	)
 }
@@ -0,0 +1,250 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/mcp"
+)
+
+const (
+	// DefaultMaxSize is the size in bytes after which the log is rotated
+	DefaultMaxSize = 5 * 1024 * 1024
+	// DefaultMaxFiles is the number of rotated files that are kept
+	DefaultMaxFiles = 5
+
+	maxArgLength = 2000
+)
+
+// Request is an upstream request caused by a tool call
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Entry is one audited tool call
+type Entry struct {
+	Time       time.Time              `json:"time"`
+	Tool       string                 `json:"tool"`
+	Args       map[string]interface{} `json:"args"`
+	Status     string                 `json:"status"` // "ok" or "error"
+	Error      string                 `json:"error,omitempty"`
+	DurationMS int64                  `json:"duration_ms"`
+	Reads      int                    `json:"reads"`
+	Mutations  []Request              `json:"mutations,omitempty"`
+}
+
+// Log is a size-rotated JSON lines audit log of tool calls. The MCP server
+// handles one call at a time, so upstream requests are attributed to the
+// call that is currently running.
+type Log struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mutex   sync.Mutex
+	current *Entry
+}
+
+// New creates an audit log at path that is rotated once it exceeds
+// maxSize bytes, keeping maxFiles old files
+func New(path string, maxSize int64, maxFiles int) *Log {
+	return &Log{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+}
+
+// Middleware records every tool call passing through it
+func (l *Log) Middleware(toolName string, next mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (string, error) {
+		entry := &Entry{
+			Time: time.Now(),
+			Tool: toolName,
+			Args: redact(args),
+		}
+
+		l.mutex.Lock()
+		l.current = entry
+		l.mutex.Unlock()
+
+		result, err := next(ctx, args)
+
+		l.mutex.Lock()
+		l.current = nil
+		l.mutex.Unlock()
+
+		entry.DurationMS = time.Since(entry.Time).Milliseconds()
+		entry.Status = "ok"
+		if err != nil {
+			entry.Status = "error"
+			entry.Error = err.Error()
+		}
+
+		if writeErr := l.write(entry); writeErr != nil {
+			fmt.Fprintf(os.Stderr, "audit: %v\n", writeErr)
+		}
+		return result, err
+	}
+}
+
+// RecordRequest attributes an upstream request to the running tool call.
+// Its signature matches azubiheft.RequestObserver.
+func (l *Log) RecordRequest(method, url string, status int, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.current == nil {
+		return
+	}
+
+	if method == "GET" || method == "HEAD" {
+		l.current.Reads++
+		return
+	}
+
+	req := Request{Method: method, URL: url, Status: status}
+	if err != nil {
+		req.Error = err.Error()
+	}
+	l.current.Mutations = append(l.current.Mutations, req)
+}
+
+// Query returns the entries between from and to (inclusive, zero values
+// are unbounded) for the given tool (empty for all), oldest first
+func (l *Log) Query(from, to time.Time, tool string) ([]Entry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var entries []Entry
+	for i := l.maxFiles; i >= 0; i-- {
+		found, err := readEntries(l.rotatedPath(i), from, to, tool)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, found...)
+	}
+	return entries, nil
+}
+
+func (l *Log) write(entry *Entry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	if info, err := os.Stat(l.path); err == nil && info.Size()+int64(len(data)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts audit.jsonl to audit.jsonl.1, audit.jsonl.1 to
+// audit.jsonl.2 and so on, dropping the oldest file
+func (l *Log) rotate() error {
+	if err := os.Remove(l.rotatedPath(l.maxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove old log: %w", err)
+	}
+	for i := l.maxFiles - 1; i >= 0; i-- {
+		err := os.Rename(l.rotatedPath(i), l.rotatedPath(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate log: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l *Log) rotatedPath(i int) string {
+	if i == 0 {
+		return l.path
+	}
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+func readEntries(path string, from, to time.Time, tool string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if !from.IsZero() && e.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && e.Time.After(to) {
+			continue
+		}
+		if tool != "" && e.Tool != tool {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+	return entries, nil
+}
+
+// redact copies the arguments, hiding credentials and shortening long text
+func redact(args map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "password") || strings.Contains(lower, "token") || strings.Contains(lower, "secret") {
+			redacted[key] = "[redacted]"
+			continue
+		}
+		if s, ok := value.(string); ok && len(s) > maxArgLength {
+			cut := maxArgLength
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+			value = s[:cut] + "…"
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func call(l *Log, tool string, args map[string]interface{}, err error) {
+	handler := l.Middleware(tool, func(ctx context.Context, args map[string]interface{}) (string, error) {
+		l.RecordRequest("GET", "https://www.azubiheft.de/Azubi/Tagesbericht.aspx", 200, nil)
+		l.RecordRequest("POST", "https://www.azubiheft.de/Azubi/XMLHttpRequest.ashx", 200, nil)
+		return "ok", err
+	})
+	handler(context.Background(), args)
+}
+
+func TestMiddlewareRecordsCalls(t *testing.T) {
+	l := New(filepath.Join(t.TempDir(), "audit.jsonl"), DefaultMaxSize, DefaultMaxFiles)
+	call(l, "azubiheft_write_report", map[string]interface{}{"date": "2024-05-06"}, nil)
+	call(l, "azubiheft_delete_report", map[string]interface{}{"date": "2024-05-07"}, errors.New("invalid entry number: 3"))
+
+	// Requests outside of a call are not attributed to any
+	l.RecordRequest("POST", "https://www.azubiheft.de/Login.aspx", 200, nil)
+
+	entries, err := l.Query(time.Time{}, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Query() returned %d entries, want 2", len(entries))
+	}
+	first, second := entries[0], entries[1]
+	if first.Tool != "azubiheft_write_report" || first.Status != "ok" || first.Reads != 1 || len(first.Mutations) != 1 {
+		t.Errorf("first entry = %+v", first)
+	}
+	if first.Mutations[0].Method != "POST" || first.Mutations[0].Status != 200 {
+		t.Errorf("mutation = %+v", first.Mutations[0])
+	}
+	if second.Status != "error" || second.Error != "invalid entry number: 3" {
+		t.Errorf("second entry = %+v", second)
+	}
+
+	only, err := l.Query(time.Time{}, time.Time{}, "azubiheft_delete_report")
+	if err != nil || len(only) != 1 || only[0].Args["date"] != "2024-05-07" {
+		t.Errorf("Query() by tool = %+v, %v", only, err)
+	}
+	if later, err := l.Query(time.Now().Add(time.Hour), time.Time{}, ""); err != nil || len(later) != 0 {
+		t.Errorf("Query() from the future = %+v, %v", later, err)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	// Every entry is larger than the limit, so each write rotates
+	l := New(path, 150, 2)
+	for i := 0; i < 5; i++ {
+		call(l, fmt.Sprintf("tool_%d", i), map[string]interface{}{"n": float64(i)}, nil)
+	}
+
+	for _, name := range []string{"audit.jsonl", "audit.jsonl.1", "audit.jsonl.2"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s is missing: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("audit.jsonl.3 was kept beyond the file limit")
+	}
+
+	// The oldest calls were pruned, the rest are read oldest first across
+	// the files
+	entries, err := l.Query(time.Time{}, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	var tools []string
+	for _, e := range entries {
+		tools = append(tools, e.Tool)
+	}
+	if got, want := strings.Join(tools, " "), "tool_2 tool_3 tool_4"; got != want {
+		t.Errorf("tools = %s, want %s", got, want)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	long := strings.Repeat("ä", maxArgLength)
+	args := map[string]interface{}{
+		"username":           "max",
+		"password":           "geheim",
+		"Password_Hash":      "x",
+		"confirmation_token": "abc",
+		"api_secret":         "x",
+		"message":            long,
+		"entry_type":         float64(1),
+	}
+	got := redact(args)
+
+	for _, key := range []string{"password", "Password_Hash", "confirmation_token", "api_secret"} {
+		if got[key] != "[redacted]" {
+			t.Errorf("%s = %v, want it redacted", key, got[key])
+		}
+	}
+	if got["username"] != "max" || got["entry_type"] != float64(1) {
+		t.Errorf("other arguments changed: %v", got)
+	}
+	message := got["message"].(string)
+	if !strings.HasSuffix(message, "…") || len(message) > maxArgLength+len("…") || !utf8.ValidString(message) {
+		t.Errorf("long text was not shortened on a rune boundary: %d bytes", len(message))
+	}
+	if args["password"] != "geheim" {
+		t.Error("redact changed the arguments passed to the tool")
+	}
+}
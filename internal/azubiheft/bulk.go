@@ -0,0 +1,163 @@
+package azubiheft
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// EntrySpec describes an entry to be written
+type EntrySpec struct {
+	Message   string `json:"message"`
+	TimeSpent string `json:"time_spent"`
+	EntryType int    `json:"entry_type"`
+}
+
+// Day result states of WriteRange
+const (
+	DayWritten = "written"
+	DayPresent = "already_present"
+	DaySkipped = "skipped"
+	DayFailed  = "failed"
+)
+
+// RangeOptions controls WriteRange
+type RangeOptions struct {
+	// Weekdays to write; empty means Monday to Friday
+	Weekdays []time.Weekday
+	// Skip may exclude single days, e.g. holidays, and return the reason
+	Skip func(date time.Time) (reason string, skip bool)
+	// Entries are written on every selected day
+	Entries []EntrySpec
+	// Wrap, if set, runs around the writes of each day, e.g. to journal them
+	Wrap func(date time.Time, write func() error) error
+}
+
+// DayResult is the outcome of WriteRange for a single day
+type DayResult struct {
+	Date   time.Time
+	Status string
+	Reason string
+}
+
+// WriteRange writes the same entries on every selected day between from
+// and to (inclusive). Week IDs are resolved once for the whole range.
+// Entries that already exist with identical type, text and duration are
+// not written again, so a partially failed range can simply be retried.
+func (s *Session) WriteRange(from, to time.Time, opts RangeOptions) ([]DayResult, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("range end %s is before its start %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	if len(opts.Entries) == 0 {
+		return nil, fmt.Errorf("no entries to write")
+	}
+
+	weekdays := opts.Weekdays
+	if len(weekdays) == 0 {
+		weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	selected := make(map[time.Weekday]bool, len(weekdays))
+	for _, d := range weekdays {
+		selected[d] = true
+	}
+
+	weeks, err := s.GetWeeks()
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.GetSubjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DayResult
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if !selected[date.Weekday()] {
+			continue
+		}
+		if opts.Skip != nil {
+			if reason, skip := opts.Skip(date); skip {
+				results = append(results, DayResult{Date: date, Status: DaySkipped, Reason: reason})
+				continue
+			}
+		}
+
+		results = append(results, s.writeDay(weeks, subjects, date, opts))
+	}
+
+	return results, nil
+}
+
+func (s *Session) writeDay(weeks []Week, subjects []Subject, date time.Time, opts RangeOptions) DayResult {
+	if _, err := s.weekID(weeks, date); err != nil {
+		return DayResult{Date: date, Status: DayFailed, Reason: err.Error()}
+	}
+
+	existing, err := s.GetReport(date, true)
+	if err != nil {
+		return DayResult{Date: date, Status: DayFailed, Reason: err.Error()}
+	}
+
+	var missing []EntrySpec
+	for _, spec := range opts.Entries {
+		if !containsEntry(existing, subjects, spec) {
+			missing = append(missing, spec)
+		}
+	}
+	if len(missing) == 0 {
+		return DayResult{Date: date, Status: DayPresent}
+	}
+
+	write := func() error {
+		return s.WriteEntries(date, weeks, missing)
+	}
+
+	if opts.Wrap != nil {
+		err = opts.Wrap(date, write)
+	} else {
+		err = write()
+	}
+	if err != nil {
+		return DayResult{Date: date, Status: DayFailed, Reason: err.Error()}
+	}
+
+	return DayResult{Date: date, Status: DayWritten}
+}
+
+// containsEntry reports whether an entry with the spec's type, text and
+// duration already exists. Entries name their type, so the spec's type ID
+// is looked up in subjects. The text is compared after a Markdown round
+// trip so that formatting differences do not matter.
+func containsEntry(entries []ReportEntry, subjects []Subject, spec EntrySpec) bool {
+	typeName := ""
+	for _, subject := range subjects {
+		if subject.ID == strconv.Itoa(spec.EntryType) {
+			typeName = subject.Name
+		}
+	}
+
+	text := HTMLToMarkdown(MarkdownToHTML(spec.Message))
+	for _, e := range entries {
+		if e.Type == typeName && e.Duration == spec.TimeSpent && e.Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteEntries writes entries for a single day, resolving the week ID from
+// weeks as returned by GetWeeks. Callers writing many days fetch the week
+// list once and pass it to every call.
+func (s *Session) WriteEntries(date time.Time, weeks []Week, entries []EntrySpec) error {
+	weekID, err := s.weekID(weeks, date)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range entries {
+		if err := s.writeEntry(date, weekID, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package azubiheft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriteRangeComparesEntryType(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	session := NewSession(WithBaseURL(srv.URL))
+	date := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+
+	// The same text and duration, but at school
+	site.entries["20240506"] = []fakeEntry{{seq: 1, typeName: "Schule", duration: "08:00", content: "<div>Projektarbeit</div>"}}
+
+	opts := RangeOptions{Entries: []EntrySpec{{Message: "Projektarbeit", TimeSpent: "08:00", EntryType: SubjectBetrieb}}}
+	for i, want := range []string{DayWritten, DayPresent} {
+		results, err := session.WriteRange(date, date, opts)
+		if err != nil {
+			t.Fatalf("WriteRange: %v", err)
+		}
+		if len(results) != 1 || results[0].Status != want {
+			t.Errorf("run %d: results %+v, want %s", i+1, results, want)
+		}
+	}
+
+	entries := site.entries["20240506"]
+	if len(entries) != 2 || entries[1].typeName != "Betrieb" {
+		t.Errorf("entries on the site: %+v", entries)
+	}
+}
+
+func TestWriteEntriesSkipsEmptyDuration(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	session := NewSession(WithBaseURL(srv.URL))
+	date := time.Date(2024, time.May, 7, 0, 0, 0, 0, time.UTC)
+
+	weeks, err := session.GetWeeks()
+	if err != nil {
+		t.Fatalf("GetWeeks: %v", err)
+	}
+	specs := []EntrySpec{
+		{Message: "leer", TimeSpent: "00:00", EntryType: SubjectBetrieb},
+		{Message: "Lager", TimeSpent: "01:00", EntryType: SubjectBetrieb},
+	}
+	if err := session.WriteEntries(date, weeks, specs); err != nil {
+		t.Fatalf("WriteEntries: %v", err)
+	}
+
+	entries := site.entries["20240507"]
+	if len(entries) != 1 || entries[0].duration != "01:00" {
+		t.Errorf("entries on the site: %+v", entries)
+	}
+}
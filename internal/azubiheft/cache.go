@@ -0,0 +1,129 @@
+package azubiheft
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cache keeps pages a session has read. The cache decides how long they stay
+// fresh; on a miss the session reads the page from the site again and stores
+// the result.
+type Cache interface {
+	Weeks() ([]Week, bool)
+	PutWeeks(weeks []Week)
+	InvalidateWeeks()
+
+	Subjects() ([]Subject, bool)
+	PutSubjects(subjects []Subject)
+	InvalidateSubjects()
+
+	Report(date time.Time, formatted bool) ([]ReportEntry, bool)
+	PutReport(date time.Time, formatted bool, entries []ReportEntry)
+	InvalidateReport(date time.Time)
+}
+
+// WithCache serves reads from c while they are fresh. Mutations of the
+// session invalidate exactly the pages they change.
+func WithCache(c Cache) Option {
+	return func(s *Session) {
+		s.cache = c
+	}
+}
+
+// GetSubjects retrieves all subjects
+func (s *Session) GetSubjects() ([]Subject, error) {
+	if s.cache != nil {
+		if subjects, ok := s.cache.Subjects(); ok {
+			return subjects, nil
+		}
+	}
+	subjects, err := s.fetchSubjects()
+	if err == nil && s.cache != nil {
+		s.cache.PutSubjects(subjects)
+	}
+	return subjects, err
+}
+
+// GetWeeks lists all report weeks of the training period
+func (s *Session) GetWeeks() ([]Week, error) {
+	if s.cache != nil {
+		if weeks, ok := s.cache.Weeks(); ok {
+			return weeks, nil
+		}
+	}
+	weeks, err := s.fetchWeeks()
+	if err == nil && s.cache != nil {
+		s.cache.PutWeeks(weeks)
+	}
+	return weeks, err
+}
+
+// GetReport retrieves the entries of a day. With includeFormatting the texts
+// are returned as Markdown, otherwise as plain text.
+func (s *Session) GetReport(date time.Time, includeFormatting bool) ([]ReportEntry, error) {
+	if s.cache != nil {
+		if entries, ok := s.cache.Report(date, includeFormatting); ok {
+			return entries, nil
+		}
+	}
+	entries, err := s.fetchReport(date, includeFormatting)
+	if err == nil && s.cache != nil {
+		s.cache.PutReport(date, includeFormatting, entries)
+	}
+	return entries, err
+}
+
+// FetchSubjects reads the subjects from the site, bypassing the cache, and
+// refreshes the cached list. Confirmations must be built from it.
+func (s *Session) FetchSubjects() ([]Subject, error) {
+	subjects, err := s.fetchSubjects()
+	if err == nil && s.cache != nil {
+		s.cache.PutSubjects(subjects)
+	}
+	return subjects, err
+}
+
+// FetchReport reads the entries of a day from the site, bypassing the
+// cache, and refreshes the cached day. Undo snapshots and confirmations
+// must be built from it, never from a page that may be stale.
+func (s *Session) FetchReport(date time.Time, includeFormatting bool) ([]ReportEntry, error) {
+	entries, err := s.fetchReport(date, includeFormatting)
+	if err == nil && s.cache != nil {
+		s.cache.PutReport(date, includeFormatting, entries)
+	}
+	return entries, err
+}
+
+// invalidate drops cached pages after a mutating request. Dry runs change
+// nothing, so their cache stays valid.
+func (s *Session) invalidate(fn func(Cache)) {
+	if s.cache != nil && !s.dryRun {
+		fn(s.cache)
+	}
+}
+
+// checkEntries makes sure entries about to be deleted, which may have been
+// read from the cache, still exist unchanged on the site. Deletes address
+// entries by Seq, so a stale Seq could hit another entry.
+func (s *Session) checkEntries(date time.Time, entries []ReportEntry) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	current, err := s.fetchReport(date, true)
+	if err != nil {
+		return err
+	}
+	bySeq := make(map[string]ReportEntry, len(current))
+	for _, e := range current {
+		bySeq[e.Seq] = e
+	}
+	for _, e := range entries {
+		c, ok := bySeq[e.Seq]
+		if !ok || c.Type != e.Type || c.Duration != e.Duration || c.HTML != e.HTML {
+			s.cache.InvalidateReport(date)
+			return fmt.Errorf("entries of %s changed on the site since they were read, read the report again", date.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package azubiheft
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PlannedRequest is a mutating request that a dry-run session recorded
+// instead of sending
+type PlannedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body"`
+}
+
+// String formats the request with its decoded form fields
+func (p PlannedRequest) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", p.Method, p.URL)
+
+	values, err := url.ParseQuery(p.Body)
+	if err != nil {
+		fmt.Fprintf(&b, "\n    %s", p.Body)
+		return b.String()
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		// ASP.NET view state tokens are large and uninteresting
+		if !strings.HasPrefix(key, "__") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, v := range values[key] {
+			fmt.Fprintf(&b, "\n    %s=%s", key, v)
+		}
+	}
+	return b.String()
+}
+
+// DryRun returns a session sharing the same login that performs all reads
+// but records mutating requests instead of sending them
+func (s *Session) DryRun() *Session {
+	return &Session{
+		client:   s.client,
+		baseURL:  s.baseURL,
+		dryRun:   true,
+		readOnly: s.readOnly,
+		cache:    s.cache,
+	}
+}
+
+// IsDryRun reports whether mutations of this session are only recorded
+func (s *Session) IsDryRun() bool {
+	return s.dryRun
+}
+
+// PlannedRequests returns the mutations recorded by a dry-run session
+func (s *Session) PlannedRequests() []PlannedRequest {
+	return s.planned
+}
+
+// send performs a mutating request. Every request that changes the account
+// must go through here so that dry runs cannot leak.
+func (s *Session) send(req *http.Request) (*http.Response, error) {
+	if s.readOnly {
+		return nil, fmt.Errorf("%w: refusing %s %s", ErrReadOnly, req.Method, req.URL.Path)
+	}
+	if !s.dryRun {
+		return s.client.Do(req)
+	}
+
+	var body string
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		body = string(data)
+	}
+
+	s.planned = append(s.planned, PlannedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   body,
+	})
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// postForm is the mutating counterpart of http.Client.PostForm
+func (s *Session) postForm(reqURL string, formData url.Values) (*http.Response, error) {
+	req, err := http.NewRequest("POST", reqURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return s.send(req)
+}
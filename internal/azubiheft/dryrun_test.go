@@ -0,0 +1,88 @@
+package azubiheft
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDryRunRecordsRequests(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	site.entries["20240506"] = []fakeEntry{{seq: 3, duration: "08:00", content: "<div>Support</div>"}}
+	session := NewSession(WithBaseURL(srv.URL)).DryRun()
+	date := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+
+	if !session.IsDryRun() {
+		t.Fatal("IsDryRun() = false")
+	}
+	if err := session.WriteReport(date, "Schule", "02:00", SubjectSchule); err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	if err := session.DeleteReport(date, nil); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+
+	planned := session.PlannedRequests()
+	if len(planned) != 2 {
+		t.Fatalf("recorded %d requests, want 2: %v", len(planned), planned)
+	}
+	for _, p := range planned {
+		if p.Method != "POST" || !strings.Contains(p.URL, "/Azubi/XMLHttpRequest.ashx") {
+			t.Errorf("recorded %s %s", p.Method, p.URL)
+		}
+	}
+	write, err := url.ParseQuery(planned[0].Body)
+	if err != nil {
+		t.Fatalf("write body: %v", err)
+	}
+	if write.Get("Seq") != "0" || write.Get("Art_ID") != "2" || write.Get("Dauer") != "02:00" {
+		t.Errorf("write request = %v", write)
+	}
+	if !strings.Contains(planned[0].String(), "\n    Dauer=02:00") {
+		t.Errorf("String() does not list the form fields:\n%s", planned[0])
+	}
+
+	// Reads reach the site, mutations do not
+	if len(site.entries["20240506"]) != 1 || len(site.deleted) != 0 {
+		t.Errorf("dry run changed the site: %+v, deleted %q", site.entries["20240506"], site.deleted)
+	}
+}
+
+func TestReadOnlyRefusesMutations(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	site.entries["20240506"] = []fakeEntry{{seq: 3, duration: "08:00", content: "<div>Support</div>"}}
+	date := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+
+	for _, session := range []*Session{
+		NewSession(WithBaseURL(srv.URL), ReadOnly()),
+		// A dry run of a read-only session must not record instead
+		NewSession(WithBaseURL(srv.URL), ReadOnly()).DryRun(),
+	} {
+		mutations := map[string]func() error{
+			"WriteReport":   func() error { return session.WriteReport(date, "Schule", "02:00", SubjectSchule) },
+			"DeleteReport":  func() error { return session.DeleteReport(date, nil) },
+			"AddSubject":    func() error { return session.AddSubject("Projekt") },
+			"DeleteSubject": func() error { return session.DeleteSubject("8") },
+		}
+		for name, mutate := range mutations {
+			if err := mutate(); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("%s (dry run %v) = %v, want ErrReadOnly", name, session.IsDryRun(), err)
+			}
+		}
+		if len(session.PlannedRequests()) != 0 {
+			t.Errorf("read-only session recorded %v", session.PlannedRequests())
+		}
+
+		// Reads still work
+		if entries, err := session.GetReport(date, true); err != nil || len(entries) != 1 {
+			t.Errorf("GetReport() = %+v, %v", entries, err)
+		}
+	}
+	if len(site.entries["20240506"]) != 1 || len(site.deleted) != 0 {
+		t.Errorf("read-only session changed the site: %+v, deleted %q", site.entries["20240506"], site.deleted)
+	}
+}
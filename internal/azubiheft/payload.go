@@ -0,0 +1,87 @@
+package azubiheft
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// entryPayload is the form body XMLHttpRequest.ashx expects when an entry
+// is created (Seq "0") or deleted (negative Seq)
+type entryPayload struct {
+	Seq      string
+	TypeID   int
+	Duration string
+	Content  string // editor HTML, see MarkdownToHTML
+}
+
+// encode builds the urlencoded request body. The site's editor script runs
+// Inhalt through encodeURIComponent before submitting the form, so the
+// content is encoded once by encodeContent and once more as a form value.
+func (p entryPayload) encode() string {
+	formData := url.Values{
+		"disablePaste": {"0"},
+		"Seq":          {p.Seq},
+		"Art_ID":       {strconv.Itoa(p.TypeID)},
+		"Abt_ID":       {"0"},
+		"Dauer":        {p.Duration},
+		"Inhalt":       {encodeContent(p.Content)},
+		"jsVer":        {"12"},
+	}
+	return formData.Encode()
+}
+
+// encodeContent mirrors JavaScript's encodeURIComponent
+func encodeContent(s string) string {
+	const unreserved = "-_.!~*'()"
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(unreserved, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// postEntry sends a single entry mutation for the given day
+func (s *Session) postEntry(date time.Time, weekID string, payload entryPayload) error {
+	reqURL := fmt.Sprintf("%s/Azubi/XMLHttpRequest.ashx?Datum=%s&BrNr=%s&BrSt=1&BrVorh=Yes&T=%d",
+		s.baseURL, date.Format("20060102"), weekID, time.Now().Unix())
+
+	req, err := http.NewRequest("POST", reqURL, strings.NewReader(payload.encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("x-my-ajax-request", "ajax")
+	req.Header.Set("Origin", s.baseURL)
+	req.Header.Set("Referer", s.baseURL)
+	req.Header.Set("Sec-Fetch-Dest", "empty")
+	req.Header.Set("Sec-Fetch-Mode", "cors")
+	req.Header.Set("Sec-Fetch-Site", "same-origin")
+	req.Header.Set("Pragma", "no-cache")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := s.send(req)
+	s.invalidate(func(c Cache) { c.InvalidateReport(date) })
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status code %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
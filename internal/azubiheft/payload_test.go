@@ -0,0 +1,279 @@
+package azubiheft
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSite stands in for the pages of Azubiheft that reports are written
+// through and read from. It stores the Inhalt of each entry as submitted by
+// the editor script, after undoing its encodeURIComponent.
+type fakeSite struct {
+	t       *testing.T
+	mutex   sync.Mutex
+	entries map[string][]fakeEntry // by Datum
+	nextSeq int
+	deleted []string // Inhalt of delete requests
+}
+
+type fakeEntry struct {
+	seq      int
+	typeName string // Betrieb if empty
+	duration string
+	content  string
+}
+
+// fakeTypes names the default entry types by Art_ID
+var fakeTypes = map[string]string{"1": "Betrieb", "2": "Schule", "3": "ÜBA", "4": "Urlaub", "5": "Feiertag", "6": "Arbeitsunfähig", "7": "Frei"}
+
+func newFakeSite(t *testing.T) *httptest.Server {
+	site := &fakeSite{t: t, entries: make(map[string][]fakeEntry), nextSeq: 1}
+	srv := httptest.NewServer(site)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (f *fakeSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch r.URL.Path {
+	case "/Azubi/Ausbildungsnachweise.aspx":
+		fmt.Fprint(w, `<div class="mo NBox" onclick="location='Wochenansicht.aspx?NachweisNr=4711'"><div class="KW"><div>KW</div><div class="sKW">19</div><div>2024</div></div></div>`)
+	case "/Azubi/Tagesbericht.aspx":
+		for _, e := range f.entries[r.URL.Query().Get("Datum")] {
+			typeName := e.typeName
+			if typeName == "" {
+				typeName = "Betrieb"
+			}
+			fmt.Fprintf(w, `<div class="d0 mo" data-seq="%d"><div class="row1 d3">Art: %s</div><div class="row2 d4">%s</div><div class="row7 d5">%s</div></div>`,
+				e.seq, html.EscapeString(typeName), html.EscapeString(e.duration), e.content)
+		}
+	case "/Azubi/XMLHttpRequest.ashx":
+		if err := r.ParseForm(); err != nil {
+			f.t.Errorf("invalid form: %v", err)
+			return
+		}
+		// decodeURIComponent, which leaves + alone
+		content, err := url.PathUnescape(r.PostForm.Get("Inhalt"))
+		if err != nil {
+			f.t.Errorf("invalid Inhalt %q: %v", r.PostForm.Get("Inhalt"), err)
+			return
+		}
+		date := r.URL.Query().Get("Datum")
+		seq := r.PostForm.Get("Seq")
+		if seq == "0" {
+			f.entries[date] = append(f.entries[date], fakeEntry{
+				seq:      f.nextSeq,
+				typeName: fakeTypes[r.PostForm.Get("Art_ID")],
+				duration: r.PostForm.Get("Dauer"),
+				content:  content,
+			})
+			f.nextSeq++
+			return
+		}
+		f.deleted = append(f.deleted, content)
+		n, _ := strconv.Atoi(strings.TrimPrefix(seq, "-"))
+		kept := f.entries[date][:0]
+		for _, e := range f.entries[date] {
+			if e.seq != n {
+				kept = append(kept, e)
+			}
+		}
+		f.entries[date] = kept
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	texts := []struct {
+		name string
+		text string
+	}{
+		{"umlauts", "Übergabe an die Qualitätssicherung, Maße geprüft, Größe ändern"},
+		{"emoji", "Deployment erledigt 🚀 Team-Feedback 👍🏽"},
+		{"percent", "100% der Tickets, Rabatt 20%, %20 bleibt %20"},
+		{"plus", "a+b=c, C++ und +49 170 1234567"},
+		{"quotes", `"Zitat" und 'Apostroph', „deutsch“ und ‚einfach‘`},
+		{"multiline", "Zeile 1\nZeile 2\n\nZeile 4"},
+		{"markup characters", "if a < b && c > d { return }"},
+		{"lists", "**Aufgaben**\n- Lager\n  - Inventur\n1. Versand"},
+	}
+
+	srv := newFakeSite(t)
+	session := NewSession(WithBaseURL(srv.URL))
+	date := time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range texts {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := session.WriteReport(date, tt.text, "01:30", SubjectBetrieb); err != nil {
+				t.Fatalf("WriteReport: %v", err)
+			}
+
+			entries, err := session.GetReport(date, true)
+			if err != nil {
+				t.Fatalf("GetReport: %v", err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			if entries[0].Text != tt.text {
+				t.Errorf("read back %q, want %q", entries[0].Text, tt.text)
+			}
+			if entries[0].Duration != "01:30" {
+				t.Errorf("duration %q, want 01:30", entries[0].Duration)
+			}
+
+			if err := session.DeleteEntries(date, entries); err != nil {
+				t.Fatalf("DeleteEntries: %v", err)
+			}
+			left, err := session.GetReport(date, true)
+			if err != nil {
+				t.Fatalf("GetReport: %v", err)
+			}
+			if len(left) != 0 {
+				t.Errorf("%d entries left after delete", len(left))
+			}
+		})
+	}
+}
+
+func TestDeleteSendsStoredContent(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	session := NewSession(WithBaseURL(srv.URL))
+	date := time.Date(2024, time.May, 7, 0, 0, 0, 0, time.UTC)
+
+	// HTML the converters do not model, as if written in the site's editor
+	stored := `<div style="color: red">Rot <u>unterstrichen</u></div><table><tbody><tr><td>1</td></tr></tbody></table>`
+	site.entries["20240507"] = []fakeEntry{{seq: 9, duration: "02:00", content: stored}}
+
+	entries, err := session.GetReport(date, true)
+	if err != nil {
+		t.Fatalf("GetReport: %v", err)
+	}
+	if len(entries) != 1 || entries[0].HTML != stored {
+		t.Fatalf("scraped %+v, want HTML %q", entries, stored)
+	}
+
+	if err := session.DeleteReport(date, nil); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	if len(site.deleted) != 1 || site.deleted[0] != stored {
+		t.Errorf("delete sent Inhalt %q, want %q", site.deleted, stored)
+	}
+}
+
+func TestEncodeContent(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// Expected values as produced by encodeURIComponent
+		{"abc-_.!~*'()", "abc-_.!~*'()"},
+		{"a b+c%", "a%20b%2Bc%25"},
+		{"ä€🚀", "%C3%A4%E2%82%AC%F0%9F%9A%80"},
+		{"<div>\"&\"</div>\n", "%3Cdiv%3E%22%26%22%3C%2Fdiv%3E%0A"},
+	}
+	for _, tt := range tests {
+		if got := encodeContent(tt.in); got != tt.want {
+			t.Errorf("encodeContent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// memoryCache is a Cache whose pages never expire
+type memoryCache struct {
+	weeks   []Week
+	reports map[string][]ReportEntry
+}
+
+func (c *memoryCache) Weeks() ([]Week, bool)        { return c.weeks, c.weeks != nil }
+func (c *memoryCache) PutWeeks(weeks []Week)        { c.weeks = weeks }
+func (c *memoryCache) InvalidateWeeks()             { c.weeks = nil }
+func (c *memoryCache) Subjects() ([]Subject, bool)  { return nil, false }
+func (c *memoryCache) PutSubjects([]Subject)        {}
+func (c *memoryCache) InvalidateSubjects()          {}
+func (c *memoryCache) InvalidateReport(d time.Time) { delete(c.reports, d.Format("20060102")) }
+
+func (c *memoryCache) Report(date time.Time, formatted bool) ([]ReportEntry, bool) {
+	entries, ok := c.reports[date.Format("20060102")]
+	return entries, ok
+}
+
+func (c *memoryCache) PutReport(date time.Time, formatted bool, entries []ReportEntry) {
+	c.reports[date.Format("20060102")] = entries
+}
+
+func TestDeleteChecksCachedContent(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	cache := &memoryCache{reports: make(map[string][]ReportEntry)}
+	session := NewSession(WithBaseURL(srv.URL), WithCache(cache))
+	date := time.Date(2024, time.May, 8, 0, 0, 0, 0, time.UTC)
+
+	site.entries["20240508"] = []fakeEntry{{seq: 3, duration: "02:00", content: "<div>Lager</div>"}}
+	cached, err := session.GetReport(date, true)
+	if err != nil {
+		t.Fatalf("GetReport: %v", err)
+	}
+
+	// Edited on the website: same Seq, type and duration, other text
+	site.entries["20240508"][0].content = "<div>Versand</div>"
+	if again, _ := session.GetReport(date, true); again[0].Text != "Lager" {
+		t.Fatalf("GetReport did not serve the cached day: %q", again[0].Text)
+	}
+	if fresh, _ := session.FetchReport(date, true); fresh[0].Text != "Versand" {
+		t.Errorf("FetchReport read %q, want Versand", fresh[0].Text)
+	}
+
+	if err := session.DeleteEntries(date, cached); err == nil {
+		t.Fatal("DeleteEntries deleted an entry whose text changed")
+	}
+	if len(site.deleted) != 0 {
+		t.Errorf("delete requests sent: %q", site.deleted)
+	}
+}
+
+func TestWriteResolvesWeekMissingFromCache(t *testing.T) {
+	srv := newFakeSite(t)
+	site := srv.Config.Handler.(*fakeSite)
+	// Cached before week 19 was added to the account
+	cache := &memoryCache{weeks: []Week{{ID: "4710", Year: 2024, Week: 18}}, reports: make(map[string][]ReportEntry)}
+	session := NewSession(WithBaseURL(srv.URL), WithCache(cache))
+	date := time.Date(2024, time.May, 9, 0, 0, 0, 0, time.UTC)
+
+	weeks, err := session.GetWeeks()
+	if err != nil {
+		t.Fatalf("GetWeeks: %v", err)
+	}
+	if err := session.WriteEntries(date, weeks, []EntrySpec{{Message: "Lager", TimeSpent: "02:00", EntryType: SubjectBetrieb}}); err != nil {
+		t.Fatalf("WriteEntries: %v", err)
+	}
+
+	results, err := session.WriteRange(date.AddDate(0, 0, 1), date.AddDate(0, 0, 1), RangeOptions{
+		Entries: []EntrySpec{{Message: "Versand", TimeSpent: "03:00", EntryType: SubjectBetrieb}},
+	})
+	if err != nil {
+		t.Fatalf("WriteRange: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != DayWritten {
+		t.Errorf("WriteRange results %+v, want written", results)
+	}
+
+	if len(site.entries["20240509"]) != 1 || len(site.entries["20240510"]) != 1 {
+		t.Errorf("entries on the site: %+v", site.entries)
+	}
+	if len(cache.weeks) != 1 || cache.weeks[0].ID != "4711" {
+		t.Errorf("cached weeks %+v, want the list read again", cache.weeks)
+	}
+}
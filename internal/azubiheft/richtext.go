@@ -0,0 +1,371 @@
+package azubiheft
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// The Azubiheft editor stores report text as a small subset of HTML: one
+// <div> per line, <b>/<i> (or <strong>/<em>) for emphasis, <ul>/<ol> lists
+// and <br> line breaks. Report text is exchanged with the assistant as a
+// matching Markdown subset, so the converters below only need to handle
+// bold, italic, bullet lists, numbered lists and line breaks. Nested lists
+// are indented by two spaces per level in Markdown and placed inside the
+// parent <li> in HTML.
+
+var (
+	bulletItemRe   = regexp.MustCompile(`^([ \t]*)[-*+]\s+(.*)$`)
+	numberedItemRe = regexp.MustCompile(`^([ \t]*)(\d+)[.)]\s+(.*)$`)
+	lineStartRe    = regexp.MustCompile(`^([-+]|\d+[.)])\s`)
+)
+
+// MarkdownToHTML converts Markdown report text into the editor's HTML.
+// All literal text is HTML-escaped, so characters like <, & or a <script>
+// tag end up as visible text instead of markup.
+func MarkdownToHTML(markdown string) string {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	lines := strings.Split(markdown, "\n")
+
+	var b strings.Builder
+	// Tags of the open lists, innermost last. Each open list has an open
+	// <li> that nested lists are written into.
+	var lists []string
+
+	closeList := func() {
+		tag := lists[len(lists)-1]
+		lists = lists[:len(lists)-1]
+		b.WriteString("</li></" + tag + ">")
+	}
+	item := func(indent, tag, text string) {
+		// A level can be at most one deeper than the current list
+		level := indentLevel(indent)
+		if level > len(lists) {
+			level = len(lists)
+		}
+		for len(lists) > level+1 {
+			closeList()
+		}
+		if len(lists) == level+1 && lists[level] != tag {
+			closeList()
+		}
+		if len(lists) == level+1 {
+			b.WriteString("</li>")
+		} else {
+			b.WriteString("<" + tag + ">")
+			lists = append(lists, tag)
+		}
+		b.WriteString("<li>" + renderInline(text))
+	}
+
+	for _, line := range lines {
+		if m := bulletItemRe.FindStringSubmatch(line); m != nil {
+			item(m[1], "ul", m[2])
+			continue
+		}
+		if m := numberedItemRe.FindStringSubmatch(line); m != nil {
+			item(m[1], "ol", m[3])
+			continue
+		}
+
+		for len(lists) > 0 {
+			closeList()
+		}
+		if strings.TrimSpace(line) == "" {
+			b.WriteString("<div><br></div>")
+			continue
+		}
+		b.WriteString("<div>" + renderInline(line) + "</div>")
+	}
+	for len(lists) > 0 {
+		closeList()
+	}
+
+	return b.String()
+}
+
+// indentLevel returns the list nesting level of a line's indentation: two
+// spaces or a tab per level
+func indentLevel(indent string) int {
+	width := 0
+	for _, c := range indent {
+		if c == '\t' {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width / 2
+}
+
+// renderInline converts emphasis markers and backslash escapes of a single
+// line into HTML, escaping everything else.
+func renderInline(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		}
+
+		if c == '*' || c == '_' {
+			if i+1 < len(s) && s[i+1] == c {
+				delim := s[i : i+2]
+				if end := findClosing(s, i+2, delim); end > i+2 {
+					b.WriteString("<b>" + renderInline(s[i+2:end]) + "</b>")
+					i = end + 2
+					continue
+				}
+				b.WriteString(delim)
+				i += 2
+				continue
+			}
+
+			if c == '*' || !isWordBefore(s, i) {
+				if end := findClosing(s, i+1, string(c)); end > i+1 {
+					b.WriteString("<i>" + renderInline(s[i+1:end]) + "</i>")
+					i = end + 1
+					continue
+				}
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(html.EscapeString(s[i : i+size]))
+		i += size
+	}
+
+	return b.String()
+}
+
+// findClosing returns the index of the delimiter closing an emphasis span
+// that starts at start, or -1 if the span is never closed.
+func findClosing(s string, start int, delim string) int {
+	for i := start; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(s[i:], delim) {
+			continue
+		}
+		if len(delim) == 1 {
+			// A single delimiter must not be half of a double one
+			if i+1 < len(s) && s[i+1] == delim[0] {
+				i++
+				continue
+			}
+			if delim == "_" && isWordAfter(s, i+1) {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+func isWordBefore(s string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return i > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isWordAfter(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return i < len(s) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// HTMLToMarkdown converts the editor's HTML into Markdown report text.
+// Unknown tags are dropped but their text is kept; Markdown control
+// characters occurring in the text are backslash-escaped.
+func HTMLToMarkdown(content string) string {
+	context := &xhtml.Node{Type: xhtml.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := xhtml.ParseFragment(strings.NewReader(content), context)
+	if err != nil {
+		return strings.TrimSpace(content)
+	}
+
+	w := &markdownWriter{}
+	for _, n := range nodes {
+		w.node(n)
+	}
+	return w.String()
+}
+
+// HTMLToText converts report HTML to plain text. Line breaks, paragraphs
+// and list markers are kept like in HTMLToMarkdown, but emphasis and
+// escapes are dropped.
+func HTMLToText(content string) string {
+	context := &xhtml.Node{Type: xhtml.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := xhtml.ParseFragment(strings.NewReader(content), context)
+	if err != nil {
+		return strings.TrimSpace(content)
+	}
+
+	w := &markdownWriter{plain: true}
+	for _, n := range nodes {
+		w.node(n)
+	}
+	return w.String()
+}
+
+// markdownWriter accumulates Markdown output line by line
+type markdownWriter struct {
+	lines   []string
+	current strings.Builder
+	lists   []listState
+	inline  bool
+	// plain writes text without Markdown syntax
+	plain bool
+}
+
+type listState struct {
+	ordered bool
+	index   int
+}
+
+func (w *markdownWriter) node(n *xhtml.Node) {
+	switch n.Type {
+	case xhtml.TextNode:
+		w.text(n.Data)
+		return
+	case xhtml.ElementNode:
+	default:
+		w.children(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		w.newline()
+	case atom.B, atom.Strong:
+		w.emphasis(n, "**")
+	case atom.I, atom.Em:
+		w.emphasis(n, "*")
+	case atom.Ul, atom.Ol:
+		w.blockBreak()
+		w.lists = append(w.lists, listState{ordered: n.DataAtom == atom.Ol})
+		w.children(n)
+		w.lists = w.lists[:len(w.lists)-1]
+		w.blockBreak()
+	case atom.Li:
+		w.blockBreak()
+		if len(w.lists) > 0 {
+			list := &w.lists[len(w.lists)-1]
+			list.index++
+			w.current.WriteString(strings.Repeat("  ", len(w.lists)-1))
+			if list.ordered {
+				w.current.WriteString(strconv.Itoa(list.index) + ". ")
+			} else {
+				w.current.WriteString("- ")
+			}
+		} else {
+			w.current.WriteString("- ")
+		}
+		w.children(n)
+		w.blockBreak()
+	case atom.Div, atom.P:
+		w.blockBreak()
+		w.children(n)
+		w.blockBreak()
+	case atom.Script, atom.Style:
+		// Never surface executable content
+	default:
+		w.children(n)
+	}
+}
+
+func (w *markdownWriter) children(n *xhtml.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+}
+
+func (w *markdownWriter) emphasis(n *xhtml.Node, delim string) {
+	inner := &markdownWriter{lists: w.lists, inline: true, plain: w.plain}
+	inner.children(n)
+	text := inner.String()
+	if w.plain || strings.TrimSpace(text) == "" || strings.Contains(text, "\n") {
+		// Emphasis cannot span lines in Markdown; keep the plain text
+		for i, line := range strings.Split(text, "\n") {
+			if i > 0 {
+				w.newline()
+			}
+			w.current.WriteString(line)
+		}
+		return
+	}
+	w.current.WriteString(delim + text + delim)
+}
+
+func (w *markdownWriter) text(s string) {
+	// Newlines in HTML source are plain whitespace; only <br> and block
+	// elements break lines
+	s = strings.NewReplacer("\u00a0", " ", "\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+	w.writeEscaped(s)
+}
+
+func (w *markdownWriter) writeEscaped(s string) {
+	if w.plain {
+		if w.current.Len() == 0 && !w.inline {
+			s = strings.TrimLeft(s, " \t")
+		}
+		w.current.WriteString(s)
+		return
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\', '*', '_':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	s = b.String()
+
+	if w.current.Len() == 0 && !w.inline {
+		// Text that would otherwise read as a list item must stay literal
+		s = strings.TrimLeft(s, " \t")
+		if m := lineStartRe.FindStringSubmatchIndex(s); m != nil {
+			markerEnd := m[3] - 1
+			s = s[:markerEnd] + `\` + s[markerEnd:]
+		}
+	}
+	w.current.WriteString(s)
+}
+
+// newline ends the current line, even if it is empty
+func (w *markdownWriter) newline() {
+	w.lines = append(w.lines, strings.TrimRight(w.current.String(), " \t"))
+	w.current.Reset()
+}
+
+// blockBreak ends the current line unless it is already empty
+func (w *markdownWriter) blockBreak() {
+	if w.current.Len() > 0 {
+		w.newline()
+	}
+}
+
+func (w *markdownWriter) String() string {
+	w.blockBreak()
+	lines := w.lines
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,109 @@
+package azubiheft
+
+import "testing"
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		html     string
+	}{
+		{"plain", "Wareneingang geprüft", "<div>Wareneingang geprüft</div>"},
+		{"emphasis", "**Inventur** und *Etiketten*", "<div><b>Inventur</b> und <i>Etiketten</i></div>"},
+		{"empty line", "Zeile 1\n\nZeile 3", "<div>Zeile 1</div><div><br></div><div>Zeile 3</div>"},
+		{"escaped text", `5 < 6 & \*kein\* Fett`, "<div>5 &lt; 6 &amp; *kein* Fett</div>"},
+		{"script", "<script>alert(1)</script>", "<div>&lt;script&gt;alert(1)&lt;/script&gt;</div>"},
+		{"bullet list", "- a\n- b", "<ul><li>a</li><li>b</li></ul>"},
+		{"numbered list", "1. a\n2. b", "<ol><li>a</li><li>b</li></ol>"},
+		{
+			"nested bullets",
+			"- a\n  - b\n  - c\n- d",
+			"<ul><li>a<ul><li>b</li><li>c</li></ul></li><li>d</li></ul>",
+		},
+		{
+			"mixed nesting",
+			"1. a\n  - b\n    1. c\n2. d",
+			"<ol><li>a<ul><li>b<ol><li>c</li></ol></li></ul></li><li>d</li></ol>",
+		},
+		{
+			"nested list before text",
+			"- a\n  - b\nText",
+			"<ul><li>a<ul><li>b</li></ul></li></ul><div>Text</div>",
+		},
+		{
+			"list type changes on a level",
+			"- a\n  - b\n  1. c",
+			"<ul><li>a<ul><li>b</li></ul><ol><li>c</li></ol></li></ul>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := MarkdownToHTML(tt.markdown)
+			if html != tt.html {
+				t.Errorf("MarkdownToHTML(%q) = %q, want %q", tt.markdown, html, tt.html)
+			}
+			if got := HTMLToMarkdown(html); got != tt.markdown {
+				t.Errorf("HTMLToMarkdown(%q) = %q, want %q", html, got, tt.markdown)
+			}
+		})
+	}
+}
+
+func TestHTMLToMarkdownNestedLists(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			"list inside item",
+			"<ul><li>a<ul><li>b</li></ul></li><li>c</li></ul>",
+			"- a\n  - b\n- c",
+		},
+		{
+			// contenteditable places nested lists next to the items
+			"list next to item",
+			"<ul><li>a</li><ul><li>b</li><li>c</li></ul><li>d</li></ul>",
+			"- a\n  - b\n  - c\n- d",
+		},
+		{
+			"numbers restart per list",
+			"<ol><li>a<ol><li>b</li><li>c</li></ol></li><li>d</li></ol>",
+			"1. a\n  1. b\n  2. c\n2. d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HTMLToMarkdown(tt.html)
+			if got != tt.want {
+				t.Errorf("HTMLToMarkdown(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+			// The Markdown must be stable once it has been converted
+			if again := HTMLToMarkdown(MarkdownToHTML(got)); again != got {
+				t.Errorf("round trip of %q = %q", got, again)
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTMLIndentation(t *testing.T) {
+	tests := []struct {
+		markdown string
+		want     string
+	}{
+		// Too deep an indentation nests only one level
+		{"- a\n      - b", "<ul><li>a<ul><li>b</li></ul></li></ul>"},
+		// A tab counts as one level
+		{"- a\n\t- b", "<ul><li>a<ul><li>b</li></ul></li></ul>"},
+		// An indented first item starts at the top level
+		{"  - a", "<ul><li>a</li></ul>"},
+	}
+
+	for _, tt := range tests {
+		if got := MarkdownToHTML(tt.markdown); got != tt.want {
+			t.Errorf("MarkdownToHTML(%q) = %q, want %q", tt.markdown, got, tt.want)
+		}
+	}
+}
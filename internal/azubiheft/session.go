@@ -1,6 +1,7 @@
 package azubiheft
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,13 +15,31 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// DefaultBaseURL is the address of the Azubiheft site
+const DefaultBaseURL = "https://www.azubiheft.de"
+
+// IDs of the static subjects that every account has
 const (
-	baseURL = "https://www.azubiheft.de"
+	SubjectBetrieb        = 1
+	SubjectSchule         = 2
+	SubjectUEBA           = 3
+	SubjectUrlaub         = 4
+	SubjectFeiertag       = 5
+	SubjectArbeitsunfahig = 6
+	SubjectFrei           = 7
 )
 
+// ErrReadOnly is returned by mutating methods of a read-only session
+var ErrReadOnly = errors.New("session is read-only")
+
 // Session represents an authenticated session
 type Session struct {
-	client *http.Client
+	client   *http.Client
+	baseURL  string
+	dryRun   bool
+	readOnly bool
+	cache    Cache
+	planned  []PlannedRequest
 }
 
 // Subject represents a subject/activity type
@@ -35,25 +54,80 @@ type ReportEntry struct {
 	Type     string `json:"type"`
 	Duration string `json:"duration"`
 	Text     string `json:"text"`
+	// HTML is the content as stored on the site. Deletes send it back
+	// unchanged, since converting Text to HTML again is not exact.
+	HTML string `json:"html,omitempty"`
+}
+
+// Option configures a Session
+type Option func(*Session)
+
+// RequestObserver is notified about every request a session sends
+type RequestObserver func(method, url string, status int, err error)
+
+// WithRequestObserver reports every upstream request to observer
+func WithRequestObserver(observer RequestObserver) Option {
+	return func(s *Session) {
+		s.client.Transport = &observingTransport{
+			next:     s.client.Transport,
+			observer: observer,
+		}
+	}
+}
+
+// WithBaseURL sends all requests to another server, such as a test
+// instance, instead of DefaultBaseURL
+func WithBaseURL(url string) Option {
+	return func(s *Session) {
+		s.baseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// ReadOnly makes the session refuse every request that would change the
+// account
+func ReadOnly() Option {
+	return func(s *Session) {
+		s.readOnly = true
+	}
+}
+
+// Week is a weekly report (Ausbildungsnachweis) identified by ISO week
+type Week struct {
+	ID   string `json:"id"`
+	Year int    `json:"year"`
+	Week int    `json:"week"`
+}
+
+// Monday returns the first day of the week
+func (w Week) Monday() time.Time {
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(w.Year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(w.Week-1)*7)
 }
 
 // NewSession creates a new session
-func NewSession() *Session {
+func NewSession(opts ...Option) *Session {
 	jar, _ := cookiejar.New(nil)
-	return &Session{
+	session := &Session{
 		client: &http.Client{
 			Jar: jar,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return nil
 			},
 		},
+		baseURL: DefaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(session)
 	}
+	return session
 }
 
 // Login authenticates the user
 func (s *Session) Login(username, password string) error {
 	// Get login page for tokens
-	resp, err := s.client.Get(baseURL + "/Login.aspx")
+	resp, err := s.client.Get(s.baseURL + "/Login.aspx")
 	if err != nil {
 		return fmt.Errorf("failed to get login page: %w", err)
 	}
@@ -82,7 +156,7 @@ func (s *Session) Login(username, password string) error {
 	}
 
 	// Submit login
-	resp, err = s.client.PostForm(baseURL+"/Login.aspx", formData)
+	resp, err = s.client.PostForm(s.baseURL+"/Login.aspx", formData)
 	if err != nil {
 		return fmt.Errorf("failed to submit login: %w", err)
 	}
@@ -98,7 +172,7 @@ func (s *Session) Login(username, password string) error {
 
 // Logout terminates the session
 func (s *Session) Logout() error {
-	resp, err := s.client.Get(baseURL + "/Azubi/Abmelden.aspx")
+	resp, err := s.client.Get(s.baseURL + "/Azubi/Abmelden.aspx")
 	if err != nil {
 		return fmt.Errorf("failed to logout: %w", err)
 	}
@@ -109,7 +183,7 @@ func (s *Session) Logout() error {
 
 // IsLoggedIn checks if the session is authenticated
 func (s *Session) IsLoggedIn() bool {
-	resp, err := s.client.Get(baseURL + "/Azubi/Default.aspx")
+	resp, err := s.client.Get(s.baseURL + "/Azubi/Default.aspx")
 	if err != nil {
 		return false
 	}
@@ -123,9 +197,9 @@ func (s *Session) IsLoggedIn() bool {
 	return strings.Contains(string(body), `id="Abmelden"`)
 }
 
-// GetSubjects retrieves all subjects
-func (s *Session) GetSubjects() ([]Subject, error) {
-	resp, err := s.client.Get(baseURL + "/Azubi/SetupSchulfach.aspx")
+// fetchSubjects reads all subjects from the site
+func (s *Session) fetchSubjects() ([]Subject, error) {
+	resp, err := s.client.Get(s.baseURL + "/Azubi/SetupSchulfach.aspx")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get subjects page: %w", err)
 	}
@@ -167,7 +241,7 @@ func (s *Session) GetSubjects() ([]Subject, error) {
 // AddSubject adds a new subject
 func (s *Session) AddSubject(subjectName string) error {
 	// Get current subjects and tokens
-	resp, err := s.client.Get(baseURL + "/Azubi/SetupSchulfach.aspx")
+	resp, err := s.client.Get(s.baseURL + "/Azubi/SetupSchulfach.aspx")
 	if err != nil {
 		return fmt.Errorf("failed to get subjects page: %w", err)
 	}
@@ -203,7 +277,8 @@ func (s *Session) AddSubject(subjectName string) error {
 	timestamp := time.Now().Unix()
 	formData.Set(fmt.Sprintf("txt%d", timestamp), subjectName)
 
-	resp, err = s.client.PostForm(baseURL+"/Azubi/SetupSchulfach.aspx", formData)
+	resp, err = s.postForm(s.baseURL+"/Azubi/SetupSchulfach.aspx", formData)
+	s.invalidate(func(c Cache) { c.InvalidateSubjects() })
 	if err != nil {
 		return fmt.Errorf("failed to add subject: %w", err)
 	}
@@ -219,7 +294,7 @@ func (s *Session) AddSubject(subjectName string) error {
 // DeleteSubject deletes a subject
 func (s *Session) DeleteSubject(subjectID string) error {
 	// Get current subjects and tokens
-	resp, err := s.client.Get(baseURL + "/Azubi/SetupSchulfach.aspx")
+	resp, err := s.client.Get(s.baseURL + "/Azubi/SetupSchulfach.aspx")
 	if err != nil {
 		return fmt.Errorf("failed to get subjects page: %w", err)
 	}
@@ -255,7 +330,8 @@ func (s *Session) DeleteSubject(subjectID string) error {
 		}
 	})
 
-	resp, err = s.client.PostForm(baseURL+"/Azubi/SetupSchulfach.aspx", formData)
+	resp, err = s.postForm(s.baseURL+"/Azubi/SetupSchulfach.aspx", formData)
+	s.invalidate(func(c Cache) { c.InvalidateSubjects() })
 	if err != nil {
 		return fmt.Errorf("failed to delete subject: %w", err)
 	}
@@ -268,20 +344,21 @@ func (s *Session) DeleteSubject(subjectID string) error {
 	return nil
 }
 
-func (s *Session) GetReportWeekID(date time.Time) (string, error) {
-	resp, err := s.client.Get(baseURL + "/Azubi/Ausbildungsnachweise.aspx")
+// fetchWeeks reads all report weeks from the site
+func (s *Session) fetchWeeks() ([]Week, error) {
+	resp, err := s.client.Get(s.baseURL + "/Azubi/Ausbildungsnachweise.aspx")
 	if err != nil {
-		return "", fmt.Errorf("failed to get reports page: %w", err)
+		return nil, fmt.Errorf("failed to get reports page: %w", err)
 	}
 	defer resp.Body.Close()
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse reports page: %w", err)
+		return nil, fmt.Errorf("failed to parse reports page: %w", err)
 	}
 
-	year, week := date.ISOWeek()
-	var weekID string
+	var weeks []Week
+	re := regexp.MustCompile(`NachweisNr=(\d+)`)
 
 	doc.Find("div.mo.NBox").Each(func(i int, sel *goquery.Selection) {
 		onclick, exists := sel.Attr("onclick")
@@ -316,25 +393,59 @@ func (s *Session) GetReportWeekID(date time.Time) (string, error) {
 			return
 		}
 
-		if kw == week && kwYear == year {
-			re := regexp.MustCompile(`NachweisNr=(\d+)`)
-			matches := re.FindStringSubmatch(onclick)
-			if len(matches) >= 2 {
-				weekID = matches[1]
-			}
+		matches := re.FindStringSubmatch(onclick)
+		if len(matches) >= 2 {
+			weeks = append(weeks, Week{ID: matches[1], Year: kwYear, Week: kw})
 		}
 	})
 
-	if weekID == "" {
-		return "", fmt.Errorf("no report found for week %d/%d", week, year)
+	return weeks, nil
+}
+
+func (s *Session) GetReportWeekID(date time.Time) (string, error) {
+	weeks, err := s.GetWeeks()
+	if err != nil {
+		return "", err
 	}
 
-	return weekID, nil
+	weekID, err := findWeekID(weeks, date)
+	if err != nil && s.cache != nil {
+		// The week may have been added since the list was cached
+		s.cache.InvalidateWeeks()
+		if weeks, err = s.GetWeeks(); err != nil {
+			return "", err
+		}
+		return findWeekID(weeks, date)
+	}
+	return weekID, err
 }
 
-func (s *Session) GetReport(date time.Time, includeFormatting bool) ([]ReportEntry, error) {
+// weekID looks date up in weeks as returned by GetWeeks. A list served from
+// the cache may predate the week, which GetReportWeekID then reads again.
+func (s *Session) weekID(weeks []Week, date time.Time) (string, error) {
+	weekID, err := findWeekID(weeks, date)
+	if err != nil && s.cache != nil {
+		return s.GetReportWeekID(date)
+	}
+	return weekID, err
+}
+
+// findWeekID returns the ID of the week containing date
+func findWeekID(weeks []Week, date time.Time) (string, error) {
+	year, week := date.ISOWeek()
+	for _, w := range weeks {
+		if w.Week == week && w.Year == year {
+			return w.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no report found for week %d/%d", week, year)
+}
+
+// fetchReport reads the entries of a day from the site
+func (s *Session) fetchReport(date time.Time, includeFormatting bool) ([]ReportEntry, error) {
 	dateStr := date.Format("20060102")
-	resp, err := s.client.Get(baseURL + "/Azubi/Tagesbericht.aspx?Datum=" + dateStr)
+	resp, err := s.client.Get(s.baseURL + "/Azubi/Tagesbericht.aspx?Datum=" + dateStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get report page: %w", err)
 	}
@@ -360,12 +471,11 @@ func (s *Session) GetReport(date time.Time, includeFormatting bool) ([]ReportEnt
 		activityType = strings.TrimPrefix(activityType, "Art: ")
 
 		reportTextDiv := entry.Find("div.row7.d5")
+		htmlContent, _ := reportTextDiv.Html()
 		var text string
 
 		if includeFormatting {
-			htmlContent, _ := reportTextDiv.Html()
-			text = strings.ReplaceAll(htmlContent, "<br/>", "\n")
-			text = strings.ReplaceAll(text, "<br>", "\n")
+			text = HTMLToMarkdown(htmlContent)
 		} else {
 			text = strings.TrimSpace(reportTextDiv.Text())
 		}
@@ -375,6 +485,7 @@ func (s *Session) GetReport(date time.Time, includeFormatting bool) ([]ReportEnt
 			Type:     activityType,
 			Duration: duration,
 			Text:     text,
+			HTML:     htmlContent,
 		})
 	})
 
@@ -391,63 +502,35 @@ func (s *Session) WriteReport(date time.Time, message, timeSpent string, entryTy
 		return err
 	}
 
-	dateStr := date.Format("20060102")
-	timestamp := time.Now().Unix()
-
-	lines := strings.Split(message, "\n")
-	var formattedLines []string
-	for _, line := range lines {
-		formattedLines = append(formattedLines, "<div>"+line+"</div>")
-	}
-	formattedMessage := strings.Join(formattedLines, "")
-
-	encodedMessage := url.QueryEscape(formattedMessage)
-	encodedMessage = strings.ReplaceAll(encodedMessage, "+", "%20")
+	return s.writeEntry(date, weekID, EntrySpec{
+		Message:   message,
+		TimeSpent: timeSpent,
+		EntryType: entryType,
+	})
+}
 
-	formData := url.Values{
-		"disablePaste": {"0"},
-		"Seq":          {"0"},
-		"Art_ID":       {strconv.Itoa(entryType)},
-		"Abt_ID":       {"0"},
-		"Dauer":        {timeSpent},
-		"Inhalt":       {encodedMessage},
-		"jsVer":        {"12"},
+func (s *Session) writeEntry(date time.Time, weekID string, spec EntrySpec) error {
+	// Like WriteReport, never post empty entries; the site hides them
+	if spec.TimeSpent == "00:00" {
+		return nil
 	}
 
-	reqURL := fmt.Sprintf("%s/Azubi/XMLHttpRequest.ashx?Datum=%s&BrNr=%s&BrSt=1&BrVorh=Yes&T=%d",
-		baseURL, dateStr, weekID, timestamp)
-
-	req, err := http.NewRequest("POST", reqURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	payload := entryPayload{
+		Seq:      "0",
+		TypeID:   spec.EntryType,
+		Duration: spec.TimeSpent,
+		Content:  MarkdownToHTML(spec.Message),
 	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("x-my-ajax-request", "ajax")
-	req.Header.Set("Origin", baseURL)
-	req.Header.Set("Referer", baseURL)
-	req.Header.Set("Sec-Fetch-Dest", "empty")
-	req.Header.Set("Sec-Fetch-Mode", "cors")
-	req.Header.Set("Sec-Fetch-Site", "same-origin")
-	req.Header.Set("Pragma", "no-cache")
-	req.Header.Set("Cache-Control", "no-cache")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
+	if err := s.postEntry(date, weekID, payload); err != nil {
 		return fmt.Errorf("failed to write report: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to write report: status code %d, body: %s", resp.StatusCode, string(body))
-	}
 
 	return nil
 }
 
 func (s *Session) DeleteReport(date time.Time, entryNumber *int) error {
-	reports, err := s.GetReport(date, false)
+	// Entry numbers must refer to the current entries, never cached ones
+	reports, err := s.fetchReport(date, true)
 	if err != nil {
 		return err
 	}
@@ -461,9 +544,6 @@ func (s *Session) DeleteReport(date time.Time, entryNumber *int) error {
 		return err
 	}
 
-	dateStr := date.Format("20060102")
-	timestamp := time.Now().Unix()
-
 	var entriesToDelete []ReportEntry
 	if entryNumber == nil {
 		entriesToDelete = reports
@@ -474,38 +554,42 @@ func (s *Session) DeleteReport(date time.Time, entryNumber *int) error {
 		entriesToDelete = []ReportEntry{reports[*entryNumber-1]}
 	}
 
-	for _, entry := range entriesToDelete {
-		formData := url.Values{
-			"disablePaste": {"0"},
-			"Seq":          {fmt.Sprintf("-%s", entry.Seq)},
-			"Art_ID":       {"0"},
-			"Abt_ID":       {"0"},
-			"Dauer":        {entry.Duration},
-			"Inhalt":       {entry.Text},
-			"jsVer":        {"12"},
-		}
+	return s.deleteEntries(date, weekID, entriesToDelete)
+}
 
-		reqURL := fmt.Sprintf("%s/Azubi/XMLHttpRequest.ashx?Datum=%s&BrNr=%s&BrSt=1&BrVorh=Yes&T=%d",
-			baseURL, dateStr, weekID, timestamp)
+// DeleteEntries deletes the given entries of a day, identified by their Seq
+func (s *Session) DeleteEntries(date time.Time, entries []ReportEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
 
-		req, err := http.NewRequest("POST", reqURL, strings.NewReader(formData.Encode()))
-		if err != nil {
-			return fmt.Errorf("failed to create delete request: %w", err)
-		}
+	if err := s.checkEntries(date, entries); err != nil {
+		return err
+	}
 
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Header.Set("x-my-ajax-request", "ajax")
-		req.Header.Set("Origin", baseURL)
-		req.Header.Set("Referer", baseURL)
+	weekID, err := s.GetReportWeekID(date)
+	if err != nil {
+		return err
+	}
 
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fmt.Errorf("failed to delete report: %w", err)
-		}
-		resp.Body.Close()
+	return s.deleteEntries(date, weekID, entries)
+}
 
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("failed to delete report: status code %d", resp.StatusCode)
+func (s *Session) deleteEntries(date time.Time, weekID string, entries []ReportEntry) error {
+	for _, entry := range entries {
+		content := entry.HTML
+		if content == "" {
+			// Entries recorded before the HTML was kept
+			content = MarkdownToHTML(entry.Text)
+		}
+		payload := entryPayload{
+			Seq:      "-" + entry.Seq,
+			TypeID:   0,
+			Duration: entry.Duration,
+			Content:  content,
+		}
+		if err := s.postEntry(date, weekID, payload); err != nil {
+			return fmt.Errorf("failed to delete report: %w", err)
 		}
 	}
 
@@ -0,0 +1,24 @@
+package azubiheft
+
+import "net/http"
+
+// observingTransport reports requests to a RequestObserver
+type observingTransport struct {
+	next     http.RoundTripper
+	observer RequestObserver
+}
+
+func (t *observingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	t.observer(req.Method, req.URL.String(), status, err)
+	return resp, err
+}
@@ -0,0 +1,140 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// Version is the archive format written by Export. Read refuses archives
+// of newer versions.
+const Version = 1
+
+// Archive is a full copy of an account. Entry texts are stored as
+// Markdown, the format the tools exchange.
+type Archive struct {
+	Version    int                 `json:"version"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Subjects   []azubiheft.Subject `json:"subjects"`
+	Weeks      []Week              `json:"weeks"`
+}
+
+// Week is a weekly report with the days that have entries
+type Week struct {
+	azubiheft.Week
+	Days []Day `json:"days"`
+}
+
+// Day holds the entries of a date (YYYY-MM-DD)
+type Day struct {
+	Date    string                  `json:"date"`
+	Entries []azubiheft.ReportEntry `json:"entries"`
+}
+
+// Export reads every week and day of the account. progress, if set, is
+// called after each week.
+func Export(session *azubiheft.Session, progress func(done, total int)) (*Archive, error) {
+	return ExportRange(session, time.Time{}, time.Time{}, progress)
+}
+
+// ExportRange reads the weeks that overlap from to to (inclusive), so that
+// only their days are requested. A zero from or to leaves that side open.
+func ExportRange(session *azubiheft.Session, from, to time.Time, progress func(done, total int)) (*Archive, error) {
+	subjects, err := session.GetSubjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %w", err)
+	}
+
+	all, err := session.GetWeeks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weeks: %w", err)
+	}
+	var weeks []azubiheft.Week
+	for _, w := range all {
+		monday := w.Monday()
+		if (!from.IsZero() && monday.AddDate(0, 0, 6).Before(from)) || (!to.IsZero() && monday.After(to)) {
+			continue
+		}
+		weeks = append(weeks, w)
+	}
+
+	archive := &Archive{
+		Version:    Version,
+		ExportedAt: time.Now().UTC(),
+		Subjects:   subjects,
+	}
+	for i, w := range weeks {
+		week := Week{Week: w, Days: []Day{}}
+		monday := w.Monday()
+		for d := 0; d < 7; d++ {
+			date := monday.AddDate(0, 0, d)
+			entries, err := session.GetReport(date, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get report for %s: %w", date.Format("2006-01-02"), err)
+			}
+			if len(entries) > 0 {
+				week.Days = append(week.Days, Day{Date: date.Format("2006-01-02"), Entries: entries})
+			}
+		}
+		archive.Weeks = append(archive.Weeks, week)
+		if progress != nil {
+			progress(i+1, len(weeks))
+		}
+	}
+	return archive, nil
+}
+
+// Write stores the archive at path
+func Write(path string, archive *Archive) error {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to create archive directory: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// Read loads the archive at path
+func Read(path string) (*Archive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to parse archive: %w", err)
+	}
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d, this build reads version %d", archive.Version, Version)
+	}
+	return &archive, nil
+}
+
+// Entries counts the entries in the archive
+func (a *Archive) Entries() int {
+	n := 0
+	for _, w := range a.Weeks {
+		for _, d := range w.Days {
+			n += len(d.Entries)
+		}
+	}
+	return n
+}
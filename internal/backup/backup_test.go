@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// weekSite lists weeks 18 to 20 of 2024 and counts the day pages read
+type weekSite struct {
+	mutex sync.Mutex
+	days  []string
+}
+
+func (s *weekSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch r.URL.Path {
+	case "/Azubi/Ausbildungsnachweise.aspx":
+		for kw := 18; kw <= 20; kw++ {
+			fmt.Fprintf(w, `<div class="mo NBox" onclick="location='Wochenansicht.aspx?NachweisNr=%d'"><div class="KW"><div>KW</div><div class="sKW">%d</div><div>2024</div></div></div>`, 4700+kw, kw)
+		}
+	case "/Azubi/Tagesbericht.aspx":
+		date := r.URL.Query().Get("Datum")
+		s.days = append(s.days, date)
+		if date == "20240507" {
+			fmt.Fprint(w, `<div class="d0 mo" data-seq="1"><div class="row1 d3">Art: Betrieb</div><div class="row2 d4">08:00</div><div class="row7 d5"><div>Lager</div></div></div>`)
+		}
+	case "/Azubi/SetupSchulfach.aspx":
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestExportRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		weeks    int
+	}{
+		{"whole account", "", "", 3},
+		{"one day", "2024-05-07", "2024-05-07", 1},
+		{"open end", "2024-05-12", "", 2},
+		{"open start", "", "2024-05-05", 1},
+		{"no week", "2025-01-01", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := &weekSite{}
+			srv := httptest.NewServer(site)
+			defer srv.Close()
+			session := azubiheft.NewSession(azubiheft.WithBaseURL(srv.URL))
+
+			var from, to time.Time
+			if tt.from != "" {
+				from, _ = time.Parse("2006-01-02", tt.from)
+			}
+			if tt.to != "" {
+				to, _ = time.Parse("2006-01-02", tt.to)
+			}
+
+			archive, err := ExportRange(session, from, to, nil)
+			if err != nil {
+				t.Fatalf("ExportRange: %v", err)
+			}
+			if len(archive.Weeks) != tt.weeks {
+				t.Errorf("got %d weeks, want %d", len(archive.Weeks), tt.weeks)
+			}
+			if len(site.days) != 7*tt.weeks {
+				t.Errorf("read %d day pages, want %d", len(site.days), 7*tt.weeks)
+			}
+			if tt.weeks == 1 && tt.from == "2024-05-07" && archive.Entries() != 1 {
+				t.Errorf("archive has %d entries, want 1", archive.Entries())
+			}
+		})
+	}
+}
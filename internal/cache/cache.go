@@ -0,0 +1,407 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// Version is the file format written by a Store. Files of other versions
+// are discarded. Version 2 keeps the HTML of entries.
+const Version = 2
+
+// saveInterval limits how often reads are written to disk. Invalidations
+// are written at once.
+const saveInterval = 5 * time.Second
+
+// Store is a file-backed cache of the weeks, days and subjects of one or
+// more accounts. Every page is kept with the hash of its content and the
+// time it was fetched; pages older than the TTL are read again.
+type Store struct {
+	path  string
+	ttl   time.Duration
+	mutex sync.Mutex
+
+	loaded bool
+	data   file
+	dirty  bool
+	saved  time.Time
+	err    error
+	stats  Stats
+}
+
+type file struct {
+	Version  int                 `json:"version"`
+	Accounts map[string]*account `json:"accounts"`
+}
+
+type account struct {
+	Weeks    *item           `json:"weeks,omitempty"`
+	Subjects *item           `json:"subjects,omitempty"`
+	Days     map[string]*day `json:"days,omitempty"` // by date, YYYY-MM-DD
+}
+
+// day holds both renderings of a day's entries, which are fetched
+// separately
+type day struct {
+	Formatted *item `json:"formatted,omitempty"`
+	Plain     *item `json:"plain,omitempty"`
+}
+
+type item struct {
+	Data    json.RawMessage `json:"data"`
+	Hash    string          `json:"hash"`
+	Fetched time.Time       `json:"fetched"`
+}
+
+// Stats describes the content of a store and how it was used since it was
+// opened
+type Stats struct {
+	Accounts int
+	Weeks    int // accounts with a cached week list
+	Subjects int // accounts with a cached subject list
+	Days     int
+	Fresh    int // cached pages younger than the TTL
+
+	Hits      int
+	Misses    int
+	Changed   int // refreshed pages whose content differed
+	Unchanged int // refreshed pages whose content was the same
+}
+
+// Open returns a store persisted at path whose pages stay fresh for ttl.
+// The file is read on first use and created on the first write; an
+// unreadable file is treated as empty.
+func Open(path string, ttl time.Duration) *Store {
+	return &Store{path: path, ttl: ttl}
+}
+
+// TTL returns how long cached pages are served
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Account returns the cache of one account. name must identify the account
+// and the site, so that different logins never share pages.
+func (s *Store) Account(name string) *Account {
+	return &Account{store: s, name: name}
+}
+
+// Stats returns the current content and usage counters
+func (s *Store) Stats() Stats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	stats := s.stats
+	stats.Accounts = len(s.data.Accounts)
+	for _, a := range s.data.Accounts {
+		if a.Weeks != nil {
+			stats.Weeks++
+			stats.Fresh += s.countFresh(a.Weeks)
+		}
+		if a.Subjects != nil {
+			stats.Subjects++
+			stats.Fresh += s.countFresh(a.Subjects)
+		}
+		for _, d := range a.Days {
+			stats.Days++
+			stats.Fresh += s.countFresh(d.Formatted) + s.countFresh(d.Plain)
+		}
+	}
+	return stats
+}
+
+// Clear removes every cached page
+func (s *Store) Clear() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	s.data.Accounts = make(map[string]*account)
+	s.dirty = true
+	return s.save()
+}
+
+// ClearDays removes the cached days from from to to (inclusive) of every
+// account and returns how many were removed
+func (s *Store) ClearDays(from, to time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	removed := 0
+	for _, a := range s.data.Accounts {
+		for date := range a.Days {
+			if date >= first && date <= last {
+				delete(a.Days, date)
+				removed++
+			}
+		}
+	}
+	if removed > 0 {
+		s.dirty = true
+	}
+	return removed, s.save()
+}
+
+// Flush writes pending changes to disk. It returns the last error that
+// occurred while saving in the background.
+func (s *Store) Flush() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.save(); err != nil {
+		return err
+	}
+	err := s.err
+	s.err = nil
+	return err
+}
+
+func (s *Store) countFresh(it *item) int {
+	if it != nil && time.Since(it.Fetched) < s.ttl {
+		return 1
+	}
+	return 0
+}
+
+// load reads the file once. The cache only saves requests, so a missing,
+// damaged or outdated file simply starts it empty.
+func (s *Store) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+
+	if data, err := os.ReadFile(s.path); err == nil {
+		if json.Unmarshal(data, &s.data) != nil || s.data.Version != Version {
+			s.data = file{}
+		}
+	}
+	s.data.Version = Version
+	if s.data.Accounts == nil {
+		s.data.Accounts = make(map[string]*account)
+	}
+}
+
+// save writes the file if anything changed
+func (s *Store) save() error {
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	s.dirty = false
+	s.saved = time.Now()
+	return nil
+}
+
+// changed marks the store dirty. Invalidations are saved at once so that a
+// restart never serves a page the account no longer has; other changes are
+// batched.
+func (s *Store) changed(now bool) {
+	s.dirty = true
+	if !now && time.Since(s.saved) < saveInterval {
+		return
+	}
+	if err := s.save(); err != nil {
+		s.err = err
+	}
+}
+
+// get decodes a fresh item into v
+func (s *Store) get(it *item, v interface{}) bool {
+	if it == nil || time.Since(it.Fetched) >= s.ttl || json.Unmarshal(it.Data, v) != nil {
+		s.stats.Misses++
+		return false
+	}
+	s.stats.Hits++
+	return true
+}
+
+// put stores v in *slot. A refreshed page with the same content only gets a
+// new fetch time.
+func (s *Store) put(slot **item, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	if old := *slot; old != nil {
+		if old.Hash == hash {
+			s.stats.Unchanged++
+		} else {
+			s.stats.Changed++
+		}
+	}
+	*slot = &item{Data: data, Hash: hash, Fetched: time.Now()}
+	s.changed(false)
+}
+
+// Account is the cache of a single account. It implements azubiheft.Cache.
+type Account struct {
+	store *Store
+	name  string
+}
+
+var _ azubiheft.Cache = (*Account)(nil)
+
+// account returns the stored data of a, creating it if needed. The store
+// must be locked.
+func (a *Account) account() *account {
+	a.store.load()
+	acc, ok := a.store.data.Accounts[a.name]
+	if !ok {
+		acc = &account{}
+		a.store.data.Accounts[a.name] = acc
+	}
+	if acc.Days == nil {
+		acc.Days = make(map[string]*day)
+	}
+	return acc
+}
+
+// day returns the cached day of date, creating it if needed. The store must
+// be locked.
+func (a *Account) day(date time.Time) *day {
+	days := a.account().Days
+	key := date.Format("2006-01-02")
+	d, ok := days[key]
+	if !ok {
+		d = &day{}
+		days[key] = d
+	}
+	return d
+}
+
+// Weeks returns the cached week list while it is fresh
+func (a *Account) Weeks() ([]azubiheft.Week, bool) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	var weeks []azubiheft.Week
+	ok := a.store.get(a.account().Weeks, &weeks)
+	return weeks, ok
+}
+
+// PutWeeks stores a freshly read week list
+func (a *Account) PutWeeks(weeks []azubiheft.Week) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	a.store.put(&a.account().Weeks, weeks)
+}
+
+// InvalidateWeeks drops the cached week list
+func (a *Account) InvalidateWeeks() {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	acc := a.account()
+	if acc.Weeks != nil {
+		acc.Weeks = nil
+		a.store.changed(true)
+	}
+}
+
+// Subjects returns the cached subjects while they are fresh
+func (a *Account) Subjects() ([]azubiheft.Subject, bool) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	var subjects []azubiheft.Subject
+	ok := a.store.get(a.account().Subjects, &subjects)
+	return subjects, ok
+}
+
+// PutSubjects stores freshly read subjects
+func (a *Account) PutSubjects(subjects []azubiheft.Subject) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	a.store.put(&a.account().Subjects, subjects)
+}
+
+// InvalidateSubjects drops the cached subjects
+func (a *Account) InvalidateSubjects() {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	acc := a.account()
+	if acc.Subjects != nil {
+		acc.Subjects = nil
+		a.store.changed(true)
+	}
+}
+
+// Report returns the cached entries of a day while they are fresh
+func (a *Account) Report(date time.Time, formatted bool) ([]azubiheft.ReportEntry, bool) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	acc := a.account()
+	d, ok := acc.Days[date.Format("2006-01-02")]
+	if !ok {
+		a.store.stats.Misses++
+		return nil, false
+	}
+	it := d.Plain
+	if formatted {
+		it = d.Formatted
+	}
+
+	var entries []azubiheft.ReportEntry
+	ok = a.store.get(it, &entries)
+	return entries, ok
+}
+
+// PutReport stores freshly read entries of a day
+func (a *Account) PutReport(date time.Time, formatted bool, entries []azubiheft.ReportEntry) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	d := a.day(date)
+	if formatted {
+		a.store.put(&d.Formatted, entries)
+	} else {
+		a.store.put(&d.Plain, entries)
+	}
+}
+
+// InvalidateReport drops both renderings of a day
+func (a *Account) InvalidateReport(date time.Time) {
+	a.store.mutex.Lock()
+	defer a.store.mutex.Unlock()
+
+	days := a.account().Days
+	key := date.Format("2006-01-02")
+	if _, ok := days[key]; ok {
+		delete(days, key)
+		a.store.changed(true)
+	}
+}
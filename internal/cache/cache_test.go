@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+var (
+	monday  = time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	tuesday = monday.AddDate(0, 0, 1)
+	entries = []azubiheft.ReportEntry{{Seq: "1", Type: "Betrieb", Duration: "08:00", Text: "Support", HTML: "<div>Support</div>"}}
+)
+
+func TestFreshness(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "cache.json"), 50*time.Millisecond)
+	a := s.Account("max@https://www.azubiheft.de")
+
+	if _, ok := a.Report(monday, true); ok {
+		t.Fatal("empty cache served a day")
+	}
+	a.PutReport(monday, true, entries)
+	got, ok := a.Report(monday, true)
+	if !ok || !reflect.DeepEqual(got, entries) {
+		t.Fatalf("Report() = %+v, %v, want the stored entries", got, ok)
+	}
+	// The plain rendering is cached on its own
+	if _, ok := a.Report(monday, false); ok {
+		t.Error("plain rendering served from the formatted one")
+	}
+	// Accounts never share pages
+	if _, ok := s.Account("erika@https://www.azubiheft.de").Report(monday, true); ok {
+		t.Error("another account was served the day")
+	}
+
+	stats := s.Stats()
+	if stats.Hits != 1 || stats.Misses != 3 || stats.Days != 1 || stats.Fresh != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit, 3 misses and 1 fresh day", stats)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := a.Report(monday, true); ok {
+		t.Error("expired day was served")
+	}
+	if stats := s.Stats(); stats.Fresh != 0 || stats.Days != 1 {
+		t.Errorf("Stats() after the TTL = %+v, want the day kept but not fresh", stats)
+	}
+}
+
+func TestChangedAndUnchanged(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "cache.json"), time.Hour)
+	a := s.Account("max")
+
+	a.PutWeeks([]azubiheft.Week{{ID: "4711", Year: 2024, Week: 19}})
+	a.PutWeeks([]azubiheft.Week{{ID: "4711", Year: 2024, Week: 19}})
+	a.PutWeeks([]azubiheft.Week{{ID: "4711", Year: 2024, Week: 19}, {ID: "4712", Year: 2024, Week: 20}})
+	a.PutReport(monday, true, entries)
+	a.PutReport(monday, true, nil)
+
+	stats := s.Stats()
+	if stats.Unchanged != 1 || stats.Changed != 2 {
+		t.Errorf("Stats() = %+v, want 1 unchanged and 2 changed", stats)
+	}
+	if weeks, ok := a.Weeks(); !ok || len(weeks) != 2 {
+		t.Errorf("Weeks() = %+v, %v, want the latest list", weeks, ok)
+	}
+	if got, ok := a.Report(monday, true); !ok || len(got) != 0 {
+		t.Errorf("Report() = %+v, %v, want a cached empty day", got, ok)
+	}
+
+	a.InvalidateWeeks()
+	if _, ok := a.Weeks(); ok {
+		t.Error("invalidated week list was served")
+	}
+}
+
+func TestClearDays(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "cache.json"), time.Hour)
+	for _, name := range []string{"max", "erika"} {
+		a := s.Account(name)
+		a.PutSubjects([]azubiheft.Subject{{ID: "1", Name: "Betrieb"}})
+		for _, date := range []time.Time{monday, tuesday, monday.AddDate(0, 0, 7)} {
+			a.PutReport(date, true, entries)
+		}
+	}
+
+	removed, err := s.ClearDays(monday, tuesday)
+	if err != nil {
+		t.Fatalf("ClearDays: %v", err)
+	}
+	if removed != 4 {
+		t.Errorf("ClearDays() removed %d days, want 4", removed)
+	}
+	a := s.Account("max")
+	if _, ok := a.Report(tuesday, true); ok {
+		t.Error("cleared day was served")
+	}
+	if _, ok := a.Report(monday.AddDate(0, 0, 7), true); !ok {
+		t.Error("day after the range was cleared")
+	}
+	if _, ok := a.Subjects(); !ok {
+		t.Error("ClearDays dropped the subjects")
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if stats := s.Stats(); stats.Accounts != 0 || stats.Days != 0 {
+		t.Errorf("Stats() after Clear = %+v", stats)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "cache.json")
+	s := Open(path, time.Hour)
+	a := s.Account("max")
+	a.PutReport(monday, true, entries)
+	a.PutReport(tuesday, true, entries)
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	// Invalidations are written without a flush
+	a.InvalidateReport(tuesday)
+
+	reopened := Open(path, time.Hour).Account("max")
+	if got, ok := reopened.Report(monday, true); !ok || !reflect.DeepEqual(got, entries) {
+		t.Errorf("Report() after reopening = %+v, %v, want the entries with their HTML", got, ok)
+	}
+	if _, ok := reopened.Report(tuesday, true); ok {
+		t.Error("invalidated day was served after reopening")
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestDiscardedFiles(t *testing.T) {
+	tests := map[string]string{
+		"old version": `{"version":1,"accounts":{"max":{"days":{"2024-05-06":{"formatted":{"data":[],"hash":"x","fetched":"2099-01-01T00:00:00Z"}}}}}}`,
+		"damaged":     `{"version":2,"accounts":`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			s := Open(path, time.Hour)
+			if _, ok := s.Account("max").Report(monday, true); ok {
+				t.Error("day of a discarded file was served")
+			}
+			if stats := s.Stats(); stats.Days != 0 {
+				t.Errorf("Stats() = %+v, want an empty cache", stats)
+			}
+		})
+	}
+}
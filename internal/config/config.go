@@ -0,0 +1,403 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/export"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/holidays"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/timetrack"
+)
+
+// DefaultDailyHours is used when no daily_hours are configured
+const DefaultDailyHours = "08:00"
+
+// Config holds user settings that shape how entries are generated
+type Config struct {
+	// State is the Bundesland code (e.g. "NW") whose public holidays apply
+	State string `json:"state,omitempty"`
+
+	// DailyHours is the standard working time of a day in HH:MM, used for
+	// holiday and absence entries
+	DailyHours string `json:"daily_hours,omitempty"`
+
+	// VacationDaysPerYear is the yearly vacation entitlement in days
+	VacationDaysPerYear float64 `json:"vacation_days_per_year,omitempty"`
+	// TrainingStart (YYYY-MM-DD) marks the start of the apprenticeship;
+	// training years begin on its anniversary. Calendar years are used if
+	// it is not set.
+	TrainingStart string `json:"training_start,omitempty"`
+
+	// DaysOff lists additional days without entries (YYYY-MM-DD), such as
+	// company holidays, which bulk tools skip
+	DaysOff []string `json:"days_off,omitempty"`
+
+	// Templates are named weekly patterns. Each maps weekday keys such as
+	// "monday" or ranges such as "tuesday-friday" to the entries of that day.
+	Templates map[string]Template `json:"templates,omitempty"`
+
+	// CalendarRules map imported calendar events to entry types. The first
+	// matching rule applies.
+	CalendarRules []EntryRule `json:"calendar_rules,omitempty"`
+
+	// TimeTracking describes the CSV exports of a time-tracking tool
+	TimeTracking TimeTrackingConfig `json:"time_tracking,omitempty"`
+
+	// NotesDir is a folder of daily Markdown notes (YYYY-MM-DD.md) that
+	// can be synced with the report
+	NotesDir string `json:"notes_dir,omitempty"`
+
+	// Git selects the commits that drafts are generated from
+	Git GitConfig `json:"git,omitempty"`
+
+	// Trainee is printed on exported Ausbildungsnachweise
+	Trainee export.Trainee `json:"trainee,omitempty"`
+	// ExportHeader replaces the trainee lines in the header block of PDF
+	// and DOCX exports, e.g. to add a department or a report number
+	ExportHeader []export.HeaderField `json:"export_header,omitempty"`
+}
+
+// GitConfig lists local repositories and the trainee's author identities
+type GitConfig struct {
+	Repositories []string `json:"repositories,omitempty"`
+	// Authors are names or emails, matched like git log --author
+	Authors []string `json:"authors,omitempty"`
+}
+
+// Template maps weekday keys to the entries of that day
+type Template map[string][]TemplateEntry
+
+// TemplateEntry is an entry that a template creates
+type TemplateEntry struct {
+	EntryType int    `json:"entry_type"`
+	TimeSpent string `json:"time_spent"`
+	// Message is the entry text; if empty, it is generated from Subjects
+	Message string `json:"message,omitempty"`
+	// Subjects are listed in the generated text, e.g. school subjects
+	Subjects []string `json:"subjects,omitempty"`
+}
+
+// EntryRule maps imported calendar events or time-tracking rows to an
+// entry type. Match and Category are case-insensitive regular
+// expressions; a rule without either matches everything.
+type EntryRule struct {
+	// Match is tested against the text: summary, description and location
+	// of an event, or project and description of a row
+	Match string `json:"match,omitempty"`
+	// Category is tested against each category of an event, or the
+	// project of a row
+	Category  string `json:"category,omitempty"`
+	EntryType int    `json:"entry_type,omitempty"`
+	// TimeSpent overrides the duration derived from the event times; it is
+	// required for all-day events to count with other than the daily hours
+	TimeSpent string `json:"time_spent,omitempty"`
+	// Ignore drops matching events, e.g. private appointments
+	Ignore bool `json:"ignore,omitempty"`
+}
+
+// TimeTrackingConfig selects the columns of a CSV export, either from a
+// preset such as "toggl" or explicitly, and maps rows to entry types
+type TimeTrackingConfig struct {
+	Preset  string            `json:"preset,omitempty"`
+	Columns timetrack.Mapping `json:"columns,omitempty"`
+	Rules   []EntryRule       `json:"rules,omitempty"`
+}
+
+// Mapping returns the column mapping; explicit columns override those of
+// the preset
+func (t TimeTrackingConfig) Mapping(preset string) (timetrack.Mapping, error) {
+	if preset == "" {
+		preset = t.Preset
+	}
+	var m timetrack.Mapping
+	if preset != "" {
+		var ok bool
+		if m, ok = timetrack.Presets[strings.ToLower(preset)]; !ok {
+			return m, fmt.Errorf("unknown preset %q, use one of %s", preset, strings.Join(timetrack.PresetNames(), ", "))
+		}
+	}
+
+	c := t.Columns
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&m.Date, c.Date}, {&m.Duration, c.Duration}, {&m.Start, c.Start}, {&m.End, c.End},
+		{&m.Project, c.Project}, {&m.Description, c.Description},
+		{&m.DateFormat, c.DateFormat}, {&m.Delimiter, c.Delimiter},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	if m.Date == "" {
+		return m, fmt.Errorf("no columns configured, set \"time_tracking.preset\" or \"time_tracking.columns\" in the config")
+	}
+	return m, nil
+}
+
+// Matches reports whether the rule applies to an event or row
+func (r EntryRule) Matches(text string, categories []string) bool {
+	if r.Match != "" {
+		re, err := regexp.Compile("(?i)" + r.Match)
+		if err != nil || !re.MatchString(text) {
+			return false
+		}
+	}
+	if r.Category != "" {
+		re, err := regexp.Compile("(?i)" + r.Category)
+		if err != nil {
+			return false
+		}
+		matched := false
+		for _, c := range categories {
+			if re.MatchString(c) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Load reads the config file at path. A missing file yields an empty
+// config if optional is set.
+func Load(path string, optional bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.State != "" && !holidays.ValidState(c.State) {
+		return fmt.Errorf("unknown state %q, use one of %s", c.State, strings.Join(holidays.States, ", "))
+	}
+	if c.DailyHours != "" {
+		if _, err := time.Parse("15:04", c.DailyHours); err != nil {
+			return fmt.Errorf("invalid daily_hours %q, use HH:MM", c.DailyHours)
+		}
+	}
+	if c.TrainingStart != "" {
+		if _, err := time.Parse("2006-01-02", c.TrainingStart); err != nil {
+			return fmt.Errorf("invalid training_start %q, use YYYY-MM-DD", c.TrainingStart)
+		}
+	}
+	for _, day := range c.DaysOff {
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			return fmt.Errorf("invalid days_off entry %q, use YYYY-MM-DD", day)
+		}
+	}
+	if err := validateRules("calendar_rules", c.CalendarRules); err != nil {
+		return err
+	}
+	if err := validateRules("time_tracking.rules", c.TimeTracking.Rules); err != nil {
+		return err
+	}
+	if preset := c.TimeTracking.Preset; preset != "" {
+		if _, ok := timetrack.Presets[strings.ToLower(preset)]; !ok {
+			return fmt.Errorf("unknown time_tracking.preset %q, use one of %s", preset, strings.Join(timetrack.PresetNames(), ", "))
+		}
+	}
+	for i, field := range c.ExportHeader {
+		if field.Label == "" {
+			return fmt.Errorf("export_header[%d]: label is required", i)
+		}
+	}
+	for name, tmpl := range c.Templates {
+		if _, err := tmpl.Days(); err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func validateRules(key string, rules []EntryRule) error {
+	for i, rule := range rules {
+		for _, expr := range []string{rule.Match, rule.Category} {
+			if _, err := regexp.Compile("(?i)" + expr); err != nil {
+				return fmt.Errorf("%s[%d]: invalid pattern %q: %w", key, i, expr, err)
+			}
+		}
+		if !rule.Ignore && rule.EntryType <= 0 {
+			return fmt.Errorf("%s[%d]: entry_type is required", key, i)
+		}
+		if rule.TimeSpent != "" {
+			if _, err := time.Parse("15:04", rule.TimeSpent); err != nil {
+				return fmt.Errorf("%s[%d]: invalid time_spent %q, use HH:MM", key, i, rule.TimeSpent)
+			}
+		}
+	}
+	return nil
+}
+
+// StandardHours returns the configured daily working time
+func (c *Config) StandardHours() string {
+	if c.DailyHours == "" {
+		return DefaultDailyHours
+	}
+	return c.DailyHours
+}
+
+// Holiday returns the public holiday on date in the configured state
+func (c *Config) Holiday(date time.Time) (holidays.Holiday, bool) {
+	if c.State == "" {
+		return holidays.Holiday{}, false
+	}
+	return holidays.Lookup(date, c.State)
+}
+
+// TrainingYear returns the first and last day of the training year that
+// contains date
+func (c *Config) TrainingYear(date time.Time) (time.Time, time.Time) {
+	start := time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	if c.TrainingStart != "" {
+		begin, _ := time.Parse("2006-01-02", c.TrainingStart)
+		start = time.Date(date.Year(), begin.Month(), begin.Day(), 0, 0, 0, 0, time.UTC)
+		if start.After(date) {
+			start = start.AddDate(-1, 0, 0)
+		}
+	}
+	return start, start.AddDate(1, 0, -1)
+}
+
+// IsDayOff reports whether date is listed in DaysOff
+func (c *Config) IsDayOff(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	for _, d := range c.DaysOff {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Days resolves the weekday keys of the template. A weekday may only be
+// covered by one key, since it would be unclear which entries it gets.
+func (t Template) Days() (map[time.Weekday][]TemplateEntry, error) {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	days := make(map[time.Weekday][]TemplateEntry)
+	covered := make(map[time.Weekday]string)
+	for _, key := range keys {
+		entries := t[key]
+		weekdays, err := ParseWeekdayRange(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.EntryType <= 0 {
+				return nil, fmt.Errorf("%s: entry_type is required", key)
+			}
+			if e.TimeSpent == "" {
+				return nil, fmt.Errorf("%s: time_spent is required", key)
+			}
+			if err := ValidateTimeSpent(e.TimeSpent); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		for _, day := range weekdays {
+			if other, ok := covered[day]; ok {
+				if other == key {
+					continue
+				}
+				return nil, fmt.Errorf("%s is covered by both %q and %q", day, other, key)
+			}
+			covered[day] = key
+			days[day] = append(days[day], entries...)
+		}
+	}
+	return days, nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "montag": time.Monday, "mo": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "dienstag": time.Tuesday, "di": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "mittwoch": time.Wednesday, "mi": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "donnerstag": time.Thursday, "do": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "freitag": time.Friday, "fr": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "samstag": time.Saturday, "sa": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "sonntag": time.Sunday, "so": time.Sunday,
+}
+
+var timeSpentRe = regexp.MustCompile(`^\d{2}:[0-5]\d$`)
+
+// ValidateTimeSpent checks the HH:MM duration format used by Azubiheft
+func ValidateTimeSpent(timeSpent string) error {
+	if !timeSpentRe.MatchString(timeSpent) {
+		return fmt.Errorf("invalid time_spent %q, use HH:MM", timeSpent)
+	}
+	if timeSpent == "00:00" {
+		return fmt.Errorf("time_spent must not be 00:00")
+	}
+	return nil
+}
+
+// ParseWeekday accepts English and German weekday names and abbreviations
+func ParseWeekday(name string) (time.Weekday, error) {
+	day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("unknown weekday %q", name)
+	}
+	return day, nil
+}
+
+// rangeDashes turns the en and em dashes of ranges like "Tuesday–Friday"
+// into hyphens
+var rangeDashes = strings.NewReplacer("\u2013", "-", "\u2014", "-")
+
+// ParseWeekdayRange parses "monday", "tuesday-friday" or "mon,wed". Ranges
+// may also be written with an en or em dash.
+func ParseWeekdayRange(key string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(rangeDashes.Replace(key), ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := ParseWeekday(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(bounds) == 1 {
+			days = append(days, first)
+			continue
+		}
+
+		last, err := ParseWeekday(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		// Ranges may wrap over the weekend, e.g. "friday-monday"
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
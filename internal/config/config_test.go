@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWeekdayRange(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []time.Weekday
+	}{
+		{"monday", []time.Weekday{time.Monday}},
+		{"tuesday-friday", []time.Weekday{time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"Tuesday–Friday", []time.Weekday{time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"Di — Do", []time.Weekday{time.Tuesday, time.Wednesday, time.Thursday}},
+		{"mon,wed", []time.Weekday{time.Monday, time.Wednesday}},
+		{"friday-monday", []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeekdayRange(tt.key)
+		if err != nil {
+			t.Errorf("ParseWeekdayRange(%q): %v", tt.key, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWeekdayRange(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if _, err := ParseWeekdayRange("monday-someday"); err == nil {
+		t.Error("ParseWeekdayRange accepted an unknown weekday")
+	}
+}
+
+func TestLoadValidatesTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"valid", `{"templates": {"school": {"Tuesday–Friday": [{"entry_type": 2, "time_spent": "08:00"}]}}}`, ""},
+		{"bad time", `{"templates": {"school": {"monday": [{"entry_type": 2, "time_spent": "8h"}]}}}`, `invalid time_spent "8h"`},
+		{"zero time", `{"templates": {"school": {"monday": [{"entry_type": 2, "time_spent": "00:00"}]}}}`, "must not be 00:00"},
+		{"bad weekday", `{"templates": {"school": {"montag-someday": [{"entry_type": 2, "time_spent": "08:00"}]}}}`, "unknown weekday"},
+		{"overlapping keys", `{"templates": {"week": {"Mon-Fri": [{"entry_type": 1, "time_spent": "08:00"}], "Mon": [{"entry_type": 2, "time_spent": "08:00"}]}}}`, `Monday is covered by both "Mon" and "Mon-Fri"`},
+		{"same day twice", `{"templates": {"week": {"mo,di": [{"entry_type": 1, "time_spent": "08:00"}], "Dienstag": [{"entry_type": 2, "time_spent": "08:00"}]}}}`, "Tuesday is covered by both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path, false)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateDays(t *testing.T) {
+	tmpl := Template{
+		"Mon-Thu": {{EntryType: 1, TimeSpent: "08:00"}},
+		"fr,fr":   {{EntryType: 2, TimeSpent: "06:00"}, {EntryType: 1, TimeSpent: "02:00"}},
+	}
+	days, err := tmpl.Days()
+	if err != nil {
+		t.Fatalf("Days: %v", err)
+	}
+	if len(days) != 5 || len(days[time.Monday]) != 1 || len(days[time.Thursday]) != 1 {
+		t.Errorf("Days() = %v, want Monday to Friday", days)
+	}
+	// A key naming a weekday twice does not repeat its entries
+	if len(days[time.Friday]) != 2 {
+		t.Errorf("Friday has %d entries, want 2", len(days[time.Friday]))
+	}
+}
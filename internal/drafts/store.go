@@ -0,0 +1,225 @@
+package drafts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when a draft ID does not exist
+var ErrNotFound = errors.New("draft not found")
+
+// Draft is a report entry that is kept locally until it is published
+type Draft struct {
+	ID        string    `json:"id"`
+	Date      string    `json:"date"` // YYYY-MM-DD
+	Message   string    `json:"message"`
+	TimeSpent string    `json:"time_spent"`
+	EntryType int       `json:"entry_type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store is a file-backed collection of drafts
+type Store struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewStore creates a store persisted at path. The file is created on the
+// first write.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Create adds a new draft and assigns its ID
+func (s *Store) Create(draft Draft) (Draft, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drafts, err := s.load()
+	if err != nil {
+		return Draft{}, err
+	}
+
+	draft.ID = newID(drafts)
+	draft.CreatedAt = time.Now()
+	draft.UpdatedAt = draft.CreatedAt
+	drafts = append(drafts, draft)
+
+	if err := s.save(drafts); err != nil {
+		return Draft{}, err
+	}
+	return draft, nil
+}
+
+// List returns all drafts with a date between from and to (inclusive),
+// ordered by date. Empty bounds are ignored.
+func (s *Store) List(from, to string) ([]Draft, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drafts, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Draft
+	for _, d := range drafts {
+		if from != "" && d.Date < from {
+			continue
+		}
+		if to != "" && d.Date > to {
+			continue
+		}
+		result = append(result, d)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+	return result, nil
+}
+
+// Get returns the draft with the given ID
+func (s *Store) Get(id string) (Draft, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drafts, err := s.load()
+	if err != nil {
+		return Draft{}, err
+	}
+
+	for _, d := range drafts {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return Draft{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Update applies fn to the draft with the given ID and persists the result
+func (s *Store) Update(id string, fn func(*Draft)) (Draft, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drafts, err := s.load()
+	if err != nil {
+		return Draft{}, err
+	}
+
+	for i := range drafts {
+		if drafts[i].ID != id {
+			continue
+		}
+		fn(&drafts[i])
+		drafts[i].ID = id
+		drafts[i].UpdatedAt = time.Now()
+		if err := s.save(drafts); err != nil {
+			return Draft{}, err
+		}
+		return drafts[i], nil
+	}
+	return Draft{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Delete removes the drafts with the given IDs. Unknown IDs are reported
+// as an error and nothing is removed.
+func (s *Store) Delete(ids ...string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drafts, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	kept := drafts[:0]
+	for _, d := range drafts {
+		if remove[d.ID] {
+			delete(remove, d.ID)
+			continue
+		}
+		kept = append(kept, d)
+	}
+
+	if len(remove) > 0 {
+		missing := make([]string, 0, len(remove))
+		for id := range remove {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+	}
+
+	return s.save(kept)
+}
+
+func (s *Store) load() ([]Draft, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read drafts: %w", err)
+	}
+
+	var drafts []Draft
+	if err := json.Unmarshal(data, &drafts); err != nil {
+		return nil, fmt.Errorf("failed to parse drafts file %s: %w", s.path, err)
+	}
+	return drafts, nil
+}
+
+// save writes the drafts atomically so a crash never leaves a truncated file
+func (s *Store) save(drafts []Draft) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create drafts directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(drafts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode drafts: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write drafts: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write drafts: %w", err)
+	}
+	return nil
+}
+
+// newID returns a short ID that is easy to pass around in chat and does
+// not collide with an existing draft
+func newID(existing []Draft) string {
+	for {
+		id := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+		collision := false
+		for _, d := range existing {
+			if d.ID == id {
+				collision = true
+				break
+			}
+		}
+		if !collision {
+			return id
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Widths of the DOCX table columns in twentieths of a point; together they
+// fill an A4 page with 2 cm margins
+var docxColumns = []int{1500, 1300, 5638, 1200}
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>`
+
+const docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+const docxDocumentRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults>
+<w:rPrDefault><w:rPr><w:rFonts w:ascii="Arial" w:hAnsi="Arial" w:cs="Arial"/><w:sz w:val="20"/><w:szCs w:val="20"/><w:lang w:val="de-DE"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="60"/></w:pPr></w:pPrDefault>
+</w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="0" w:after="200"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="32"/><w:szCs w:val="32"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="24"/><w:szCs w:val="24"/></w:rPr></w:style>
+<w:style w:type="table" w:default="1" w:styleId="TableNormal"><w:name w:val="Normal Table"/><w:tblPr><w:tblCellMar><w:top w:w="40" w:type="dxa"/><w:left w:w="80" w:type="dxa"/><w:bottom w:w="40" w:type="dxa"/><w:right w:w="80" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>
+<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:basedOn w:val="TableNormal"/><w:tblPr><w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="808080"/><w:left w:val="single" w:sz="4" w:space="0" w:color="808080"/><w:bottom w:val="single" w:sz="4" w:space="0" w:color="808080"/><w:right w:val="single" w:sz="4" w:space="0" w:color="808080"/><w:insideH w:val="single" w:sz="4" w:space="0" w:color="808080"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="808080"/></w:tblBorders></w:tblPr></w:style>
+</w:styles>`
+
+// writeDOCX renders the report as a Word document with the same layout as
+// the PDF: a header block, a table of the entries and the hours per type
+// for each week, each week starting on a new page
+func writeDOCX(w io.Writer, r *Report, opts Options) error {
+	weeks := r.Weeks()
+	if len(weeks) == 0 {
+		return fmt.Errorf("no entries from %s to %s", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	var body bytes.Buffer
+	for i, week := range weeks {
+		if i > 0 {
+			body.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+		}
+		writeDOCXWeek(&body, week, opts)
+	}
+
+	var document bytes.Buffer
+	document.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	document.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+	document.Write(body.Bytes())
+	document.WriteString(`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="567" w:footer="567" w:gutter="0"/></w:sectPr>`)
+	document.WriteString(`</w:body></w:document>`)
+
+	zw := zip.NewWriter(w)
+	for _, part := range []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(docxContentTypes)},
+		{"_rels/.rels", []byte(docxRels)},
+		{"word/_rels/document.xml.rels", []byte(docxDocumentRels)},
+		{"word/styles.xml", []byte(docxStyles)},
+		{"word/document.xml", document.Bytes()},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeDOCXWeek(b *bytes.Buffer, week Week, opts Options) {
+	docxParagraph(b, "Heading1", "Ausbildungsnachweis "+weekTitle(week))
+
+	b.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="0" w:type="auto"/></w:tblPr><w:tblGrid><w:gridCol w:w="2400"/><w:gridCol w:w="7238"/></w:tblGrid>`)
+	for _, field := range headerFields(week, opts) {
+		b.WriteString(`<w:tr>`)
+		docxCell(b, 2400, field.Label+":", true, false)
+		docxCell(b, 7238, field.Value, false, false)
+		b.WriteString(`</w:tr>`)
+	}
+	b.WriteString(`</w:tbl>`)
+	docxParagraph(b, "", "")
+
+	b.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/><w:tblLayout w:type="fixed"/></w:tblPr><w:tblGrid>`)
+	for _, width := range docxColumns {
+		fmt.Fprintf(b, `<w:gridCol w:w="%d"/>`, width)
+	}
+	b.WriteString(`</w:tblGrid>`)
+
+	// The header row repeats on every page the table spans
+	b.WriteString(`<w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+	for i, title := range []string{"Tag", "Art", "Tätigkeit", "Stunden"} {
+		docxCell(b, docxColumns[i], title, true, true)
+	}
+	b.WriteString(`</w:tr>`)
+
+	for _, day := range week.Days {
+		for i, e := range day.Entries {
+			label := ""
+			if i == 0 {
+				label = germanWeekdays[day.Date.Weekday()] + "\n" + day.Date.Format("02.01.2006")
+			}
+			b.WriteString(`<w:tr>`)
+			docxCell(b, docxColumns[0], label, true, false)
+			docxCell(b, docxColumns[1], e.Type, false, false)
+			docxCell(b, docxColumns[2], plainText(e.Text, opts.Formatted), false, false)
+			docxCell(b, docxColumns[3], e.Duration, false, false)
+			b.WriteString(`</w:tr>`)
+		}
+	}
+	b.WriteString(`</w:tbl>`)
+
+	totals := Totals(week.Days)
+	docxParagraph(b, "Heading2", "Stunden nach Art")
+	b.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/></w:tblPr><w:tblGrid><w:gridCol w:w="3000"/><w:gridCol w:w="1200"/></w:tblGrid>`)
+	for _, t := range totals {
+		b.WriteString(`<w:tr>`)
+		docxCell(b, 3000, t.Type, false, false)
+		docxCell(b, 1200, FormatMinutes(t.Minutes), false, false)
+		b.WriteString(`</w:tr>`)
+	}
+	b.WriteString(`<w:tr>`)
+	docxCell(b, 3000, "Gesamt", true, false)
+	docxCell(b, 1200, FormatMinutes(Sum(totals)), true, false)
+	b.WriteString(`</w:tr></w:tbl>`)
+
+	// Signature lines for trainee and trainer
+	b.WriteString(`<w:p><w:pPr><w:spacing w:before="960"/></w:pPr></w:p>`)
+	b.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="0" w:type="auto"/></w:tblPr><w:tblGrid><w:gridCol w:w="4519"/><w:gridCol w:w="600"/><w:gridCol w:w="4519"/></w:tblGrid><w:tr><w:trPr><w:cantSplit/></w:trPr>`)
+	for i, label := range []string{"Datum, Unterschrift Auszubildende/r", "", "Datum, Unterschrift Ausbilder/in"} {
+		width := 4519
+		border := `<w:tcBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="000000"/></w:tcBorders>`
+		if i == 1 {
+			width, border = 600, ""
+		}
+		fmt.Fprintf(b, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/>%s</w:tcPr>`, width, border)
+		docxRuns(b, label, false, 16)
+		b.WriteString(`</w:tc>`)
+	}
+	b.WriteString(`</w:tr></w:tbl>`)
+}
+
+// docxParagraph writes a paragraph in style, or the default style if it is
+// empty
+func docxParagraph(b *bytes.Buffer, style, text string) {
+	b.WriteString(`<w:p>`)
+	if style != "" {
+		fmt.Fprintf(b, `<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+	}
+	writeDOCXText(b, text, false, 0)
+	b.WriteString(`</w:p>`)
+}
+
+func docxCell(b *bytes.Buffer, width int, text string, bold, shaded bool) {
+	fmt.Fprintf(b, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/>`, width)
+	if shaded {
+		b.WriteString(`<w:shd w:val="clear" w:color="auto" w:fill="E6E6E6"/>`)
+	}
+	b.WriteString(`</w:tcPr>`)
+	docxRuns(b, text, bold, 0)
+	b.WriteString(`</w:tc>`)
+}
+
+// docxRuns writes text as a paragraph; a cell must contain at least one
+func docxRuns(b *bytes.Buffer, text string, bold bool, size int) {
+	b.WriteString(`<w:p><w:pPr><w:spacing w:after="0"/></w:pPr>`)
+	writeDOCXText(b, text, bold, size)
+	b.WriteString(`</w:p>`)
+}
+
+// writeDOCXText writes a run of text. Line breaks become <w:br/>, which
+// Word turns back into line breaks when the text is copied.
+func writeDOCXText(b *bytes.Buffer, text string, bold bool, size int) {
+	if text == "" {
+		return
+	}
+	b.WriteString(`<w:r>`)
+	if bold || size > 0 {
+		b.WriteString(`<w:rPr>`)
+		if bold {
+			b.WriteString(`<w:b/>`)
+		}
+		if size > 0 {
+			fmt.Fprintf(b, `<w:sz w:val="%d"/><w:szCs w:val="%d"/>`, size, size)
+		}
+		b.WriteString(`</w:rPr>`)
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteString(`<w:br/>`)
+		}
+		if line == "" {
+			continue
+		}
+		b.WriteString(`<w:t xml:space="preserve">`)
+		xml.EscapeText(b, []byte(line))
+		b.WriteString(`</w:t>`)
+	}
+	b.WriteString(`</w:r>`)
+}
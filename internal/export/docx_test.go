@@ -0,0 +1,97 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// readDOCX returns the parts of a DOCX file by name
+func readDOCX(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a ZIP file: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWriteDOCX(t *testing.T) {
+	r := testReport()
+	r.Days[0].Entries = append(r.Days[0].Entries, azubiheft.ReportEntry{
+		Type:     "ÜBA",
+		Duration: "01:00",
+		Text:     "Größenmaße für Flüssigkeiten\r\nÄnderungen <geprüft> & übernommen",
+	})
+
+	var b bytes.Buffer
+	opts := Options{Trainee: Trainee{Name: "Jürgen Groß"}}
+	if err := Write(&b, FormatDOCX, r, opts); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	parts := readDOCX(t, b.Bytes())
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/styles.xml", "word/document.xml"} {
+		content, ok := parts[name]
+		if !ok {
+			t.Fatalf("%s is missing", name)
+		}
+		// Every part must be well-formed XML
+		dec := xml.NewDecoder(strings.NewReader(content))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", name, err)
+			}
+		}
+	}
+
+	document := parts["word/document.xml"]
+	for _, want := range []string{
+		// Umlauts are written as UTF-8, markup characters escaped
+		`<w:t xml:space="preserve">Größenmaße für Flüssigkeiten</w:t><w:br/><w:t xml:space="preserve">Änderungen &lt;geprüft&gt; &amp; übernommen</w:t>`,
+		`<w:t xml:space="preserve">ÜBA</w:t>`,
+		`<w:t xml:space="preserve">Jürgen Groß</w:t>`,
+		// Line breaks of entry texts and of the day label
+		`<w:t xml:space="preserve">Englisch</w:t><w:br/><w:t xml:space="preserve">Vokabeln</w:t>`,
+		`<w:t xml:space="preserve">Montag</w:t><w:br/><w:t xml:space="preserve">06.05.2024</w:t>`,
+		`<w:t xml:space="preserve">KW 20/2024</w:t>`,
+	} {
+		if !strings.Contains(document, want) {
+			t.Errorf("document.xml lacks %s", want)
+		}
+	}
+	if strings.Contains(document, "\r") {
+		t.Error("document.xml contains a carriage return")
+	}
+	// Each week after the first starts on a new page
+	if n := strings.Count(document, `<w:br w:type="page"/>`); n != 1 {
+		t.Errorf("%d page breaks, want 1", n)
+	}
+}
+
+func TestWriteDOCXWithoutEntries(t *testing.T) {
+	r := &Report{From: date("2024-05-06"), To: date("2024-05-12")}
+	if err := Write(&bytes.Buffer{}, FormatDOCX, r, Options{}); err == nil {
+		t.Error("Write() created a document without entries")
+	}
+}
@@ -0,0 +1,148 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// Supported formats
+const (
+	FormatMarkdown = "md"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	// FormatPDF is a printable Ausbildungsnachweis with a page per week
+	FormatPDF = "pdf"
+	// FormatDOCX is a Word document with a table per week
+	FormatDOCX = "docx"
+)
+
+// Formats lists the supported formats
+var Formats = []string{FormatMarkdown, FormatCSV, FormatJSON, FormatPDF, FormatDOCX}
+
+// Day holds the entries of a date
+type Day struct {
+	Date    time.Time
+	Entries []azubiheft.ReportEntry
+}
+
+// Week holds the days of a calendar week that have entries
+type Week struct {
+	Year int
+	Week int
+	Days []Day
+}
+
+// Monday returns the first day of the week
+func (w Week) Monday() time.Time {
+	return azubiheft.Week{Year: w.Year, Week: w.Week}.Monday()
+}
+
+// Total is the time spent on an entry type
+type Total struct {
+	Type    string
+	Minutes int
+}
+
+// Report holds the entries of a date range
+type Report struct {
+	From time.Time
+	To   time.Time
+	Days []Day
+}
+
+// Fetch reads the entries of every day from from to to. Days without
+// entries are left out. With formatting, entry texts are Markdown,
+// otherwise plain text.
+func Fetch(session *azubiheft.Session, from, to time.Time, formatting bool) (*Report, error) {
+	report := &Report{From: from, To: to}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		entries, err := session.GetReport(date, formatting)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get report for %s: %w", date.Format("2006-01-02"), err)
+		}
+		if len(entries) > 0 {
+			report.Days = append(report.Days, Day{Date: date, Entries: entries})
+		}
+	}
+	return report, nil
+}
+
+// Weeks groups the days of the report by ISO calendar week
+func (r *Report) Weeks() []Week {
+	var weeks []Week
+	for _, day := range r.Days {
+		year, week := day.Date.ISOWeek()
+		if n := len(weeks); n == 0 || weeks[n-1].Year != year || weeks[n-1].Week != week {
+			weeks = append(weeks, Week{Year: year, Week: week})
+		}
+		weeks[len(weeks)-1].Days = append(weeks[len(weeks)-1].Days, day)
+	}
+	return weeks
+}
+
+// Totals sums the time spent per entry type, in the order the types first
+// appear
+func Totals(days []Day) []Total {
+	var totals []Total
+	index := make(map[string]int)
+	for _, day := range days {
+		for _, e := range day.Entries {
+			i, ok := index[e.Type]
+			if !ok {
+				i = len(totals)
+				index[e.Type] = i
+				totals = append(totals, Total{Type: e.Type})
+			}
+			totals[i].Minutes += Minutes(e.Duration)
+		}
+	}
+	return totals
+}
+
+// Sum adds up totals
+func Sum(totals []Total) int {
+	sum := 0
+	for _, t := range totals {
+		sum += t.Minutes
+	}
+	return sum
+}
+
+// Minutes parses a duration given as HH:MM. Invalid durations count as 0.
+func Minutes(duration string) int {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(duration, "%d:%d", &hours, &minutes); err != nil {
+		return 0
+	}
+	return hours*60 + minutes
+}
+
+// FormatMinutes formats a number of minutes as H:MM, without a limit on
+// the hours
+func FormatMinutes(minutes int) string {
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// FormatFromPath guesses the format from the file extension of path
+func FormatFromPath(path string) (string, bool) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return "", false
+	}
+	switch strings.ToLower(path[i+1:]) {
+	case "md", "markdown":
+		return FormatMarkdown, true
+	case "csv":
+		return FormatCSV, true
+	case "json":
+		return FormatJSON, true
+	case "pdf":
+		return FormatPDF, true
+	case "docx":
+		return FormatDOCX, true
+	}
+	return "", false
+}
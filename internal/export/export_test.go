@@ -0,0 +1,265 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// testReport has entries in calendar weeks 19 and 20 of 2024
+func testReport() *Report {
+	return &Report{
+		From: date("2024-05-06"),
+		To:   date("2024-05-19"),
+		Days: []Day{
+			{Date: date("2024-05-06"), Entries: []azubiheft.ReportEntry{
+				{Type: "Betrieb", Duration: "06:00", Text: "Support"},
+				{Type: "Schule", Duration: "02:30", Text: "Mathe"},
+			}},
+			{Date: date("2024-05-07"), Entries: []azubiheft.ReportEntry{
+				{Type: "Betrieb", Duration: "08:00", Text: "Tickets *dringend*"},
+			}},
+			{Date: date("2024-05-13"), Entries: []azubiheft.ReportEntry{
+				{Type: "Schule", Duration: "08:00", Text: "Englisch\nVokabeln"},
+			}},
+		},
+	}
+}
+
+func TestWeeksAndTotals(t *testing.T) {
+	r := testReport()
+	weeks := r.Weeks()
+	if len(weeks) != 2 || weeks[0].Week != 19 || len(weeks[0].Days) != 2 || weeks[1].Week != 20 || len(weeks[1].Days) != 1 {
+		t.Fatalf("Weeks() = %+v, want weeks 19 with 2 days and 20 with 1", weeks)
+	}
+	if got := weeks[1].Monday(); !got.Equal(date("2024-05-13")) {
+		t.Errorf("Monday() = %s", got)
+	}
+
+	totals := Totals(r.Days)
+	want := []Total{{Type: "Betrieb", Minutes: 840}, {Type: "Schule", Minutes: 630}}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("Totals() = %+v, want %+v", totals, want)
+	}
+	if got := FormatMinutes(Sum(totals)); got != "24:30" {
+		t.Errorf("sum = %s, want 24:30", got)
+	}
+}
+
+func TestMinutes(t *testing.T) {
+	tests := map[string]int{"08:00": 480, "1:05": 65, "00:00": 0, "": 0, "acht": 0}
+	for in, want := range tests {
+		if got := Minutes(in); got != want {
+			t.Errorf("Minutes(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "by day",
+			opts: Options{},
+			want: `# Report 2024-05-06 – 2024-05-19
+
+## Monday 2024-05-06
+
+**Betrieb** (06:00)
+
+Support
+
+**Schule** (02:30)
+
+Mathe
+
+## Tuesday 2024-05-07
+
+**Betrieb** (08:00)
+
+Tickets \*dringend\*
+
+## Monday 2024-05-13
+
+**Schule** (08:00)
+
+Englisch  
+Vokabeln
+`,
+		},
+		{
+			name: "by week with totals",
+			opts: Options{GroupByWeek: true, Totals: true, Formatted: true},
+			want: `# Report 2024-05-06 – 2024-05-19
+
+## Week 19/2024 (2024-05-06 – 2024-05-12)
+
+### Monday 2024-05-06
+
+**Betrieb** (06:00)
+
+Support
+
+**Schule** (02:30)
+
+Mathe
+
+### Tuesday 2024-05-07
+
+**Betrieb** (08:00)
+
+Tickets *dringend*
+
+#### Totals
+
+| Type | Hours |
+| --- | ---: |
+| Betrieb | 14:00 |
+| Schule | 2:30 |
+| **Total** | **16:30** |
+
+## Week 20/2024 (2024-05-13 – 2024-05-19)
+
+### Monday 2024-05-13
+
+**Schule** (08:00)
+
+Englisch
+Vokabeln
+
+#### Totals
+
+| Type | Hours |
+| --- | ---: |
+| Schule | 8:00 |
+| **Total** | **8:00** |
+
+## Totals
+
+| Type | Hours |
+| --- | ---: |
+| Betrieb | 14:00 |
+| Schule | 10:30 |
+| **Total** | **24:30** |
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Write(&b, FormatMarkdown, testReport(), tt.opts); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, FormatCSV, testReport(), Options{GroupByWeek: true, Totals: true}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := `date,week,weekday,type,duration,text
+2024-05-06,2024-W19,Monday,Betrieb,06:00,Support
+2024-05-06,2024-W19,Monday,Schule,02:30,Mathe
+2024-05-07,2024-W19,Tuesday,Betrieb,08:00,Tickets *dringend*
+,2024-W19,,Betrieb,14:00,Total
+,2024-W19,,Schule,2:30,Total
+2024-05-13,2024-W20,Monday,Schule,08:00,"Englisch
+Vokabeln"
+,2024-W20,,Schule,8:00,Total
+,,,Betrieb,14:00,Total
+,,,Schule,10:30,Total
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	// Without grouping only the report totals are added
+	b.Reset()
+	if err := Write(&b, FormatCSV, testReport(), Options{Totals: true}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n := strings.Count(b.String(), ",Total\n"); n != 2 {
+		t.Errorf("%d total rows, want 2:\n%s", n, b.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       Options
+		wantWeeks  int
+		wantDays   int
+		wantTotals []jsonTotal
+	}{
+		{name: "by day", opts: Options{}, wantDays: 3},
+		{
+			name:      "by week with totals",
+			opts:      Options{GroupByWeek: true, Totals: true},
+			wantWeeks: 2,
+			wantTotals: []jsonTotal{
+				{Type: "Betrieb", Duration: "14:00", Minutes: 840},
+				{Type: "Schule", Duration: "10:30", Minutes: 630},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Write(&b, FormatJSON, testReport(), tt.opts); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			var got jsonReport
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+			}
+			if got.From != "2024-05-06" || got.To != "2024-05-19" || len(got.Weeks) != tt.wantWeeks || len(got.Days) != tt.wantDays {
+				t.Errorf("report %s–%s with %d weeks and %d days, want %d and %d", got.From, got.To, len(got.Weeks), len(got.Days), tt.wantWeeks, tt.wantDays)
+			}
+			if !reflect.DeepEqual(got.Totals, tt.wantTotals) {
+				t.Errorf("totals = %+v, want %+v", got.Totals, tt.wantTotals)
+			}
+			if tt.wantWeeks > 0 {
+				w := got.Weeks[1]
+				if w.Week != 20 || w.From != "2024-05-13" || w.To != "2024-05-19" || len(w.Totals) != 1 || w.Totals[0].Minutes != 480 {
+					t.Errorf("week = %+v", w)
+				}
+				if e := w.Days[0].Entries[0]; e.Text != "Englisch\nVokabeln" || w.Days[0].Weekday != "Monday" {
+					t.Errorf("day = %+v", w.Days[0])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xlsx", testReport(), Options{}); err == nil {
+		t.Error("Write() accepted an unknown format")
+	}
+	if got, ok := FormatFromPath("bericht.Markdown"); !ok || got != FormatMarkdown {
+		t.Errorf("FormatFromPath() = %q, %v", got, ok)
+	}
+	if _, ok := FormatFromPath("bericht"); ok {
+		t.Error("FormatFromPath() found a format without an extension")
+	}
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Options controls the layout of an export
+type Options struct {
+	// GroupByWeek arranges the days under their calendar week
+	GroupByWeek bool
+	// Totals adds the time spent per entry type, for each week when
+	// grouping by week and for the whole report
+	Totals bool
+	// Formatted tells that entry texts are Markdown. Plain texts are
+	// escaped in Markdown exports.
+	Formatted bool
+	// Trainee is printed in the header of printable formats
+	Trainee Trainee
+	// Header replaces the trainee lines of the header block
+	Header []HeaderField
+}
+
+// Write renders the report in format
+func Write(w io.Writer, format string, r *Report, opts Options) error {
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdown(w, r, opts)
+	case FormatCSV:
+		return writeCSV(w, r, opts)
+	case FormatJSON:
+		return writeJSON(w, r, opts)
+	case FormatPDF:
+		return writePDF(w, r, opts)
+	case FormatDOCX:
+		return writeDOCX(w, r, opts)
+	}
+	return fmt.Errorf("unsupported format %q, use %s", format, strings.Join(Formats, ", "))
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", `\<`, ">", `\>`,
+)
+
+func writeMarkdown(w io.Writer, r *Report, opts Options) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Report %s – %s\n", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+
+	writeDays := func(days []Day, heading string) {
+		for _, day := range days {
+			fmt.Fprintf(&b, "\n%s %s %s\n", heading, day.Date.Weekday(), day.Date.Format("2006-01-02"))
+			for _, e := range day.Entries {
+				text := strings.TrimSpace(e.Text)
+				if !opts.Formatted {
+					// Keep the line breaks of plain texts as hard breaks
+					text = strings.ReplaceAll(markdownEscaper.Replace(text), "\n", "  \n")
+				}
+				fmt.Fprintf(&b, "\n**%s** (%s)\n", e.Type, e.Duration)
+				if text != "" {
+					fmt.Fprintf(&b, "\n%s\n", text)
+				}
+			}
+		}
+	}
+	writeTotals := func(totals []Total, heading string) {
+		if !opts.Totals || len(totals) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s\n\n| Type | Hours |\n| --- | ---: |\n", heading)
+		for _, t := range totals {
+			fmt.Fprintf(&b, "| %s | %s |\n", t.Type, FormatMinutes(t.Minutes))
+		}
+		fmt.Fprintf(&b, "| **Total** | **%s** |\n", FormatMinutes(Sum(totals)))
+	}
+
+	if len(r.Days) == 0 {
+		b.WriteString("\nNo entries.\n")
+	}
+	if opts.GroupByWeek {
+		for _, week := range r.Weeks() {
+			monday := week.Monday()
+			fmt.Fprintf(&b, "\n## Week %d/%d (%s – %s)\n", week.Week, week.Year,
+				monday.Format("2006-01-02"), monday.AddDate(0, 0, 6).Format("2006-01-02"))
+			writeDays(week.Days, "###")
+			writeTotals(Totals(week.Days), "#### Totals")
+		}
+		writeTotals(Totals(r.Days), "## Totals")
+	} else {
+		writeDays(r.Days, "##")
+		writeTotals(Totals(r.Days), "## Totals")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCSV writes one row per entry. Totals are added as rows without a
+// date whose text is "Total".
+func writeCSV(w io.Writer, r *Report, opts Options) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "week", "weekday", "type", "duration", "text"}); err != nil {
+		return err
+	}
+
+	writeTotals := func(totals []Total, week string) {
+		if !opts.Totals {
+			return
+		}
+		for _, t := range totals {
+			cw.Write([]string{"", week, "", t.Type, FormatMinutes(t.Minutes), "Total"})
+		}
+	}
+
+	for _, week := range r.Weeks() {
+		label := fmt.Sprintf("%d-W%02d", week.Year, week.Week)
+		for _, day := range week.Days {
+			for _, e := range day.Entries {
+				cw.Write([]string{day.Date.Format("2006-01-02"), label, day.Date.Weekday().String(), e.Type, e.Duration, e.Text})
+			}
+		}
+		if opts.GroupByWeek {
+			writeTotals(Totals(week.Days), label)
+		}
+	}
+	writeTotals(Totals(r.Days), "")
+
+	cw.Flush()
+	return cw.Error()
+}
+
+type jsonEntry struct {
+	Type     string `json:"type"`
+	Duration string `json:"duration"`
+	Text     string `json:"text"`
+}
+
+type jsonDay struct {
+	Date    string      `json:"date"`
+	Weekday string      `json:"weekday"`
+	Entries []jsonEntry `json:"entries"`
+}
+
+type jsonTotal struct {
+	Type     string `json:"type"`
+	Duration string `json:"duration"`
+	Minutes  int    `json:"minutes"`
+}
+
+type jsonWeek struct {
+	Year   int         `json:"year"`
+	Week   int         `json:"week"`
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Days   []jsonDay   `json:"days"`
+	Totals []jsonTotal `json:"totals,omitempty"`
+}
+
+type jsonReport struct {
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Weeks  []jsonWeek  `json:"weeks,omitempty"`
+	Days   []jsonDay   `json:"days,omitempty"`
+	Totals []jsonTotal `json:"totals,omitempty"`
+}
+
+func writeJSON(w io.Writer, r *Report, opts Options) error {
+	days := func(days []Day) []jsonDay {
+		out := make([]jsonDay, 0, len(days))
+		for _, day := range days {
+			d := jsonDay{Date: day.Date.Format("2006-01-02"), Weekday: day.Date.Weekday().String(), Entries: []jsonEntry{}}
+			for _, e := range day.Entries {
+				d.Entries = append(d.Entries, jsonEntry{Type: e.Type, Duration: e.Duration, Text: e.Text})
+			}
+			out = append(out, d)
+		}
+		return out
+	}
+	totals := func(totals []Total) []jsonTotal {
+		if !opts.Totals {
+			return nil
+		}
+		out := make([]jsonTotal, 0, len(totals))
+		for _, t := range totals {
+			out = append(out, jsonTotal{Type: t.Type, Duration: FormatMinutes(t.Minutes), Minutes: t.Minutes})
+		}
+		return out
+	}
+
+	out := jsonReport{
+		From:   r.From.Format("2006-01-02"),
+		To:     r.To.Format("2006-01-02"),
+		Totals: totals(Totals(r.Days)),
+	}
+	if opts.GroupByWeek {
+		out.Weeks = []jsonWeek{}
+		for _, week := range r.Weeks() {
+			monday := week.Monday()
+			out.Weeks = append(out.Weeks, jsonWeek{
+				Year:   week.Year,
+				Week:   week.Week,
+				From:   monday.Format("2006-01-02"),
+				To:     monday.AddDate(0, 0, 6).Format("2006-01-02"),
+				Days:   days(week.Days),
+				Totals: totals(Totals(week.Days)),
+			})
+		}
+	} else {
+		out.Days = days(r.Days)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/pdf"
+)
+
+// Trainee identifies the trainee on printed reports
+type Trainee struct {
+	Name       string `json:"name,omitempty"`
+	Occupation string `json:"occupation,omitempty"`
+	Company    string `json:"company,omitempty"`
+	Trainer    string `json:"trainer,omitempty"`
+}
+
+// HeaderField is a line of the header block of printed reports
+type HeaderField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Printable reports whether format is printed page by page. Printable
+// formats are binary and render entry texts as plain text.
+func Printable(format string) bool {
+	return format == FormatPDF || format == FormatDOCX
+}
+
+// headerFields returns the header block of a week: the configured fields,
+// or else the trainee, followed by the week and its dates
+func headerFields(week Week, opts Options) []HeaderField {
+	fields := opts.Header
+	if len(fields) == 0 {
+		fields = []HeaderField{
+			{"Name", opts.Trainee.Name},
+			{"Ausbildungsberuf", opts.Trainee.Occupation},
+			{"Ausbildungsbetrieb", opts.Trainee.Company},
+			{"Ausbilder/in", opts.Trainee.Trainer},
+		}
+	}
+
+	monday := week.Monday()
+	fields = append(fields,
+		HeaderField{"Kalenderwoche", weekTitle(week)},
+		HeaderField{"Zeitraum", monday.Format("02.01.2006") + " – " + monday.AddDate(0, 0, 6).Format("02.01.2006")},
+	)
+
+	kept := fields[:0:0]
+	for _, f := range fields {
+		if f.Value != "" {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func weekTitle(week Week) string {
+	return fmt.Sprintf("KW %d/%d", week.Week, week.Year)
+}
+
+var germanWeekdays = [...]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}
+
+// Page layout in points
+const (
+	pdfLeft      = 50.0
+	pdfRight     = pdf.PageWidth - 50
+	pdfTop       = pdf.PageHeight - 50
+	pdfBottom    = 70.0
+	pdfTypeX     = pdfLeft + 80
+	pdfTextX     = pdfTypeX + 75
+	pdfTextWidth = pdfRight - 55 - pdfTextX
+	pdfSize      = 9.0
+	pdfLeading   = 11.5
+	// pdfSignatureY is the height of the signature lines
+	pdfSignatureY = pdfBottom + 20
+)
+
+// plainText returns the text of an entry without Markdown syntax
+func plainText(text string, formatted bool) string {
+	if !formatted {
+		return strings.TrimSpace(text)
+	}
+	return azubiheft.HTMLToText(azubiheft.MarkdownToHTML(strings.TrimSpace(text)))
+}
+
+// writePDF renders the report as an Ausbildungsnachweis: a page per week
+// with the trainee, the entries of each day, the hours per type and lines
+// for the signatures of trainee and trainer. Weeks that do not fit on a
+// page continue on the next.
+func writePDF(w io.Writer, r *Report, opts Options) error {
+	trainee := opts.Trainee
+	weeks := r.Weeks()
+	if len(weeks) == 0 {
+		return fmt.Errorf("no entries from %s to %s", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	doc := &pdf.Document{Title: "Ausbildungsnachweis", Author: trainee.Name, Created: time.Now()}
+	if trainee.Name != "" {
+		doc.Title += " " + trainee.Name
+	}
+	for _, week := range weeks {
+		writePDFWeek(doc, week, opts)
+	}
+
+	pages := doc.Pages()
+	for i, page := range pages {
+		page.TextRight(pdfRight, 35, pdf.Helvetica, 8, fmt.Sprintf("Seite %d von %d", i+1, len(pages)))
+		if trainee.Name != "" {
+			page.Text(pdfLeft, 35, pdf.Helvetica, 8, trainee.Name)
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func writePDFWeek(doc *pdf.Document, week Week, opts Options) {
+	title := weekTitle(week)
+
+	page := doc.AddPage()
+	y := pdfTop
+	page.Text(pdfLeft, y, pdf.HelveticaBold, 16, "Ausbildungsnachweis")
+	page.TextRight(pdfRight, y, pdf.HelveticaBold, 12, title)
+	y -= 26
+
+	for _, field := range headerFields(week, opts) {
+		page.Text(pdfLeft, y, pdf.HelveticaBold, pdfSize, field.Label+":")
+		page.Text(pdfLeft+100, y, pdf.Helvetica, pdfSize, field.Value)
+		y -= pdfLeading + 2
+	}
+	y -= 6
+
+	tableHeader := func() {
+		page.FillRect(pdfLeft, y-4, pdfRight-pdfLeft, pdfLeading+4, 0.9)
+		page.Text(pdfLeft+2, y, pdf.HelveticaBold, pdfSize, "Tag")
+		page.Text(pdfTypeX, y, pdf.HelveticaBold, pdfSize, "Art")
+		page.Text(pdfTextX, y, pdf.HelveticaBold, pdfSize, "Tätigkeit")
+		page.TextRight(pdfRight-2, y, pdf.HelveticaBold, pdfSize, "Stunden")
+		y -= pdfLeading + 6
+	}
+	newPage := func() {
+		page = doc.AddPage()
+		y = pdfTop
+		page.Text(pdfLeft, y, pdf.HelveticaBold, 12, "Ausbildungsnachweis "+title+" (Fortsetzung)")
+		y -= 24
+		tableHeader()
+	}
+	tableHeader()
+
+	for _, day := range week.Days {
+		label := []string{germanWeekdays[day.Date.Weekday()], day.Date.Format("02.01.2006")}
+		for _, e := range day.Entries {
+			lines := pdf.Wrap(plainText(e.Text, opts.Formatted), pdf.Helvetica, pdfSize, pdfTextWidth)
+			for i, line := range lines {
+				if y < pdfBottom {
+					newPage()
+				}
+				if len(label) > 0 {
+					page.Text(pdfLeft+2, y, pdf.HelveticaBold, pdfSize, label[0])
+					label = label[1:]
+				}
+				if i == 0 {
+					page.Text(pdfTypeX, y, pdf.Helvetica, pdfSize, e.Type)
+					page.TextRight(pdfRight-2, y, pdf.Helvetica, pdfSize, e.Duration)
+				}
+				page.Text(pdfTextX, y, pdf.Helvetica, pdfSize, line)
+				y -= pdfLeading
+			}
+			y -= 3
+		}
+		for _, line := range label {
+			// The date of a day with a single short entry
+			page.Text(pdfLeft+2, y, pdf.HelveticaBold, pdfSize, line)
+			y -= pdfLeading
+		}
+		page.Line(pdfLeft, y+pdfLeading-4, pdfRight, y+pdfLeading-4, 0.3)
+		y -= 4
+	}
+
+	totals := Totals(week.Days)
+	if y-float64(len(totals)+3)*pdfLeading < pdfSignatureY+30 {
+		newPage()
+	}
+	y -= 6
+	page.Text(pdfLeft, y, pdf.HelveticaBold, pdfSize, "Stunden nach Art")
+	y -= pdfLeading + 2
+	for _, t := range totals {
+		page.Text(pdfLeft, y, pdf.Helvetica, pdfSize, t.Type)
+		page.TextRight(pdfLeft+200, y, pdf.Helvetica, pdfSize, FormatMinutes(t.Minutes))
+		y -= pdfLeading
+	}
+	page.Line(pdfLeft, y+pdfLeading-3, pdfLeft+200, y+pdfLeading-3, 0.5)
+	page.Text(pdfLeft, y-2, pdf.HelveticaBold, pdfSize, "Gesamt")
+	page.TextRight(pdfLeft+200, y-2, pdf.HelveticaBold, pdfSize, FormatMinutes(Sum(totals)))
+
+	// Signature lines sit at the bottom of the week's last page
+	lineY := pdfSignatureY
+	mid := (pdfLeft + pdfRight) / 2
+	page.Line(pdfLeft, lineY, mid-20, lineY, 0.5)
+	page.Line(mid+20, lineY, pdfRight, lineY, 0.5)
+	page.Text(pdfLeft, lineY-11, pdf.Helvetica, 8, "Datum, Unterschrift Auszubildende/r")
+	page.Text(mid+20, lineY-11, pdf.Helvetica, 8, "Datum, Unterschrift Ausbilder/in")
+}
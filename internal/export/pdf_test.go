@@ -0,0 +1,128 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+var (
+	startxrefRe = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+	countRe     = regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`)
+	streamRe    = regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+)
+
+// checkPDF verifies the cross-reference table of a PDF and returns its
+// page count and the uncompressed content streams
+func checkPDF(t *testing.T, data []byte) (int, []string) {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+		t.Fatalf("missing PDF header")
+	}
+
+	m := startxrefRe.FindSubmatch(data)
+	if m == nil {
+		t.Fatalf("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if xref >= len(data) || !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point to the xref table", xref)
+	}
+
+	lines := strings.Split(string(data[xref:]), "\n")
+	var first, size int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &size); err != nil || first != 0 {
+		t.Fatalf("invalid xref subsection %q", lines[1])
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Errorf("invalid free entry %q", lines[2])
+	}
+	for i := 1; i < size; i++ {
+		var offset, generation int
+		if _, err := fmt.Sscanf(lines[2+i], "%010d %05d n ", &offset, &generation); err != nil || len(lines[2+i]) != 19 {
+			t.Fatalf("invalid xref entry %q", lines[2+i])
+		}
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry of object %d points to %q", i, data[offset:offset+10])
+		}
+	}
+	if !strings.Contains(string(data[xref:]), fmt.Sprintf("/Size %d ", size)) {
+		t.Errorf("trailer /Size does not match the xref table of %d entries", size)
+	}
+
+	count := countRe.FindSubmatch(data)
+	if count == nil {
+		t.Fatalf("missing page tree")
+	}
+	pages, _ := strconv.Atoi(string(count[1]))
+	if n := bytes.Count(data, []byte("/Type /Page /Parent")); n != pages {
+		t.Errorf("page tree counts %d pages, found %d", pages, n)
+	}
+
+	var streams []string
+	for _, s := range streamRe.FindAllSubmatch(data, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(s[1]))
+		if err != nil {
+			t.Fatalf("invalid content stream: %v", err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("invalid content stream: %v", err)
+		}
+		streams = append(streams, string(content))
+	}
+	return pages, streams
+}
+
+func TestWritePDF(t *testing.T) {
+	long := testReport()
+	var entries []azubiheft.ReportEntry
+	for i := 0; i < 12; i++ {
+		entries = append(entries, azubiheft.ReportEntry{Type: "Betrieb", Duration: "00:30", Text: strings.Repeat("Wartung der Server und Dokumentation im Wiki. ", 8)})
+	}
+	long.Days = append(long.Days, Day{Date: date("2024-05-14"), Entries: entries})
+
+	tests := []struct {
+		name      string
+		report    *Report
+		wantPages int
+	}{
+		{name: "a page per week", report: testReport(), wantPages: 2},
+		{name: "long week continues", report: long, wantPages: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			opts := Options{Trainee: Trainee{Name: "Jürgen Groß", Occupation: "Fachinformatiker"}}
+			if err := Write(&b, FormatPDF, tt.report, opts); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			pages, streams := checkPDF(t, b.Bytes())
+			if pages != tt.wantPages || len(streams) != tt.wantPages {
+				t.Fatalf("%d pages with %d streams, want %d", pages, len(streams), tt.wantPages)
+			}
+			if !strings.Contains(streams[0], "(KW 19/2024)") || !strings.Contains(streams[0], "(J\xfcrgen Gro\xdf)") {
+				t.Errorf("first page lacks the week or the WinAnsi-encoded trainee")
+			}
+			last := fmt.Sprintf("(Seite %d von %d)", tt.wantPages, tt.wantPages)
+			if !strings.Contains(streams[tt.wantPages-1], last) {
+				t.Errorf("last page lacks %q", last)
+			}
+		})
+	}
+}
+
+func TestWritePDFWithoutEntries(t *testing.T) {
+	r := &Report{From: date("2024-05-06"), To: date("2024-05-12")}
+	if err := Write(&bytes.Buffer{}, FormatPDF, r, Options{}); err == nil {
+		t.Error("Write() created a PDF without entries")
+	}
+}
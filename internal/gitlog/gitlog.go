@@ -0,0 +1,159 @@
+package gitlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Commit is a commit read from a local repository
+type Commit struct {
+	Hash    string
+	Repo    string
+	Author  string
+	Email   string
+	Time    time.Time
+	Subject string
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Log lists the non-merge commits of all branches of the repository at
+// path that were authored in [since, until) by one of the authors, which
+// are matched against name and email like git log --author. Without
+// authors, every commit is returned.
+func Log(ctx context.Context, path string, authors []string, since, until time.Time) ([]Commit, error) {
+	args := []string{
+		"-C", path, "log", "--all", "--no-merges",
+		// --since compares committer dates, which are never before the
+		// author date; commits rebased later must still be found, so there
+		// is no --until
+		"--since=" + since.Format(time.RFC3339),
+		"--format=%H" + fieldSep + "%an" + fieldSep + "%ae" + fieldSep + "%aI" + fieldSep + "%s" + recordSep,
+	}
+	for _, author := range authors {
+		args = append(args, "--author="+author)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git log in %s: %s", path, msg)
+		}
+		return nil, fmt.Errorf("git log in %s: %w", path, err)
+	}
+
+	repo := filepath.Base(filepath.Clean(path))
+	var commits []Commit
+	for _, record := range strings.Split(string(out), recordSep) {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		fields := strings.Split(record, fieldSep)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected git log output in %s", path)
+		}
+		t, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date %q in %s", fields[3], path)
+		}
+		if t.Before(since) || !t.Before(until) {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Repo:    repo,
+			Author:  fields[1],
+			Email:   fields[2],
+			Time:    t,
+			Subject: fields[4],
+		})
+	}
+
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	return commits, nil
+}
+
+var (
+	// conventionalRe matches prefixes like "feat: ", "fix(api)!: "
+	conventionalRe = regexp.MustCompile(`(?i)^(feat|fix|docs|style|refactor|perf|tests?|build|ci|chore|revert)(\(([^)]*)\))?!?:\s*`)
+	// issueRe matches trailing references like "(#123)"
+	issueRe = regexp.MustCompile(`\s*\(#\d+\)$`)
+)
+
+// Noise reports whether a commit says nothing about the work done, such
+// as fixup commits or "wip"
+func Noise(subject string) bool {
+	s := strings.ToLower(strings.TrimSpace(subject))
+	switch {
+	case s == "", s == "wip", s == ".", s == "update", s == "changes":
+		return true
+	case strings.HasPrefix(s, "fixup!"), strings.HasPrefix(s, "squash!"), strings.HasPrefix(s, "amend!"):
+		return true
+	}
+	return false
+}
+
+// CleanSubject turns a commit subject into report text. Conventional
+// commit prefixes are dropped, keeping the scope as context, and issue
+// references are removed.
+func CleanSubject(subject string) string {
+	s := strings.TrimSpace(subject)
+	scope := ""
+	if m := conventionalRe.FindStringSubmatch(s); m != nil {
+		scope = strings.TrimSpace(m[3])
+		s = s[len(m[0]):]
+	}
+	s = issueRe.ReplaceAllString(s, "")
+	s = strings.TrimSuffix(strings.TrimSpace(s), ".")
+	s = capitalize(s)
+	if scope != "" && s != "" {
+		s = capitalize(scope) + ": " + s
+	}
+	return s
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// Estimate guesses the working time behind a day's commit times. Commits
+// less than maxGap apart are assumed to be one stretch of work; each
+// stretch adds lead before its first commit.
+func Estimate(times []time.Time, maxGap, lead time.Duration) time.Duration {
+	if len(times) == 0 {
+		return 0
+	}
+	sorted := append([]time.Time(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	total := lead
+	for i := 1; i < len(sorted); i++ {
+		gap := sorted[i].Sub(sorted[i-1])
+		if gap < maxGap {
+			total += gap
+		} else {
+			total += lead
+		}
+	}
+	return total
+}
@@ -0,0 +1,89 @@
+package gitlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCleanSubject(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Add login page", "Add login page"},
+		{"feat: add login page", "Add login page"},
+		{"fix(api)!: handle empty responses (#42)", "Api: Handle empty responses"},
+		{"Fix(ui): Button alignment.", "Ui: Button alignment"},
+		{"chore(deps): bump goquery", "Deps: Bump goquery"},
+		{"tests: cover the parser", "Cover the parser"},
+		{"test(): add cases", "Add cases"},
+		{"refactor:   split service  ", "Split service"},
+		{"  übersetzungen ergänzt (#7)", "Übersetzungen ergänzt"},
+		{"feature: not a conventional type", "Feature: not a conventional type"},
+		{"fix #12 in parser", "Fix #12 in parser"},
+		{"docs(readme):", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CleanSubject(tt.in); got != tt.want {
+			t.Errorf("CleanSubject(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNoise(t *testing.T) {
+	tests := map[string]bool{
+		"":                              true,
+		"  ":                            true,
+		"WIP":                           true,
+		".":                             true,
+		"Update":                        true,
+		"changes":                       true,
+		"fixup! feat: add login":        true,
+		"squash! fix typo":              true,
+		"amend! docs: readme":           true,
+		"Update README":                 false,
+		"wip: login page":               false,
+		"feat: add login page":          false,
+		"Merge branch 'main' into feat": false,
+	}
+	for subject, want := range tests {
+		if got := Noise(subject); got != want {
+			t.Errorf("Noise(%q) = %v, want %v", subject, got, want)
+		}
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, err := time.Parse("15:04", clock)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	const maxGap, lead = 2 * time.Hour, 30 * time.Minute
+
+	tests := []struct {
+		name  string
+		times []string
+		want  time.Duration
+	}{
+		{"no commits", nil, 0},
+		{"one commit", []string{"10:00"}, 30 * time.Minute},
+		{"one stretch", []string{"09:00", "10:00", "11:30"}, 3 * time.Hour},
+		{"unsorted", []string{"11:30", "09:00", "10:00"}, 3 * time.Hour},
+		{"two stretches", []string{"08:00", "09:00", "14:00", "15:00"}, 3 * time.Hour},
+		{"gap of exactly maxGap splits", []string{"08:00", "10:00"}, time.Hour},
+		{"same time", []string{"08:00", "08:00"}, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		var times []time.Time
+		for _, c := range tt.times {
+			times = append(times, at(c))
+		}
+		if got := Estimate(times, maxGap, lead); got != tt.want {
+			t.Errorf("%s: Estimate() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
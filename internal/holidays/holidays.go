@@ -0,0 +1,180 @@
+package holidays
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// States are the codes of the 16 Bundesländer
+var States = []string{
+	"BW", // Baden-Württemberg
+	"BY", // Bayern
+	"BE", // Berlin
+	"BB", // Brandenburg
+	"HB", // Bremen
+	"HH", // Hamburg
+	"HE", // Hessen
+	"MV", // Mecklenburg-Vorpommern
+	"NI", // Niedersachsen
+	"NW", // Nordrhein-Westfalen
+	"RP", // Rheinland-Pfalz
+	"SL", // Saarland
+	"SN", // Sachsen
+	"ST", // Sachsen-Anhalt
+	"SH", // Schleswig-Holstein
+	"TH", // Thüringen
+}
+
+// Holiday is a statutory public holiday
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// ValidState reports whether code is one of States
+func ValidState(code string) bool {
+	code = strings.ToUpper(code)
+	for _, s := range States {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// rule describes when a holiday applies
+type rule struct {
+	name string
+	date func(year int, easter time.Time) time.Time
+	// states in which the holiday applies; nil means nationwide
+	states []string
+	// applies optionally restricts the years, e.g. for new holidays
+	applies func(year int, state string) bool
+}
+
+func fixed(month time.Month, day int) func(int, time.Time) time.Time {
+	return func(year int, _ time.Time) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func easterOffset(days int) func(int, time.Time) time.Time {
+	return func(_ int, easter time.Time) time.Time {
+		return easter.AddDate(0, 0, days)
+	}
+}
+
+func since(year int) func(int, string) bool {
+	return func(y int, _ string) bool { return y >= year }
+}
+
+// Only statutory holidays valid for a whole state are listed. Holidays
+// that only apply in some municipalities, such as Mariä Himmelfahrt in
+// parts of Bayern or Fronleichnam in parts of Sachsen and Thüringen, are
+// not included.
+var rules = []rule{
+	{name: "Neujahr", date: fixed(time.January, 1)},
+	{name: "Heilige Drei Könige", date: fixed(time.January, 6), states: []string{"BW", "BY", "ST"}},
+	{name: "Internationaler Frauentag", date: fixed(time.March, 8), states: []string{"BE", "MV"},
+		applies: func(year int, state string) bool {
+			return state == "BE" && year >= 2019 || state == "MV" && year >= 2023
+		}},
+	{name: "Karfreitag", date: easterOffset(-2)},
+	{name: "Ostersonntag", date: easterOffset(0), states: []string{"BB", "HE"}},
+	{name: "Ostermontag", date: easterOffset(1)},
+	{name: "Tag der Arbeit", date: fixed(time.May, 1)},
+	{name: "Tag der Befreiung", date: fixed(time.May, 8), states: []string{"BE"},
+		applies: func(year int, _ string) bool { return year == 2020 || year == 2025 }},
+	{name: "Christi Himmelfahrt", date: easterOffset(39)},
+	{name: "Pfingstsonntag", date: easterOffset(49), states: []string{"BB", "HE"}},
+	{name: "Pfingstmontag", date: easterOffset(50)},
+	{name: "Fronleichnam", date: easterOffset(60), states: []string{"BW", "BY", "HE", "NW", "RP", "SL"}},
+	{name: "Mariä Himmelfahrt", date: fixed(time.August, 15), states: []string{"SL"}},
+	{name: "Weltkindertag", date: fixed(time.September, 20), states: []string{"TH"}, applies: since(2019)},
+	{name: "Tag der Deutschen Einheit", date: fixed(time.October, 3)},
+	{name: "Reformationstag", date: fixed(time.October, 31),
+		applies: func(year int, state string) bool {
+			switch state {
+			case "BB", "MV", "SN", "ST", "TH":
+				return true
+			case "HB", "HH", "NI", "SH":
+				// Permanent since 2018, after the anniversary in 2017
+				return year >= 2017
+			}
+			// 500th anniversary of the Reformation
+			return year == 2017
+		}},
+	{name: "Allerheiligen", date: fixed(time.November, 1), states: []string{"BW", "BY", "NW", "RP", "SL"}},
+	{name: "Buß- und Bettag", date: repentanceDay, states: []string{"SN"}},
+	{name: "1. Weihnachtsfeiertag", date: fixed(time.December, 25)},
+	{name: "2. Weihnachtsfeiertag", date: fixed(time.December, 26)},
+}
+
+// ForYear returns the public holidays of a state in a year, ordered by date
+func ForYear(year int, state string) []Holiday {
+	state = strings.ToUpper(state)
+	easter := Easter(year)
+
+	var result []Holiday
+	for _, r := range rules {
+		if r.states != nil && !contains(r.states, state) {
+			continue
+		}
+		if r.applies != nil && !r.applies(year, state) {
+			continue
+		}
+		result = append(result, Holiday{Date: r.date(year, easter), Name: r.name})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result
+}
+
+// Lookup returns the holiday on date in a state, if any
+func Lookup(date time.Time, state string) (Holiday, bool) {
+	for _, h := range ForYear(date.Year(), state) {
+		if h.Date.Year() == date.Year() && h.Date.YearDay() == date.YearDay() {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// Easter returns Easter Sunday of the Gregorian calendar, using the
+// anonymous Gregorian algorithm (Meeus/Jones/Butcher)
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// repentanceDay is the Wednesday before November 23rd
+func repentanceDay(year int, _ time.Time) time.Time {
+	nov22 := time.Date(year, time.November, 22, 0, 0, 0, 0, time.UTC)
+	back := (int(nov22.Weekday()) - int(time.Wednesday) + 7) % 7
+	return nov22.AddDate(0, 0, -back)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestEaster(t *testing.T) {
+	tests := map[int]string{
+		1818: "1818-03-22", // earliest possible date
+		1943: "1943-04-25", // latest possible date
+		2000: "2000-04-23",
+		2008: "2008-03-23",
+		2011: "2011-04-24",
+		2019: "2019-04-21",
+		2023: "2023-04-09",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2026: "2026-04-05",
+		2038: "2038-04-25",
+	}
+	for year, want := range tests {
+		if got := Easter(year); !got.Equal(date(want)) {
+			t.Errorf("Easter(%d) = %s, want %s", year, got.Format("2006-01-02"), want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		date  string
+		state string
+		want  string // "" means no holiday
+	}{
+		// Easter-based holidays
+		{"2024-03-29", "NW", "Karfreitag"},
+		{"2024-04-01", "HB", "Ostermontag"},
+		{"2025-05-29", "SH", "Christi Himmelfahrt"},
+		{"2025-06-09", "TH", "Pfingstmontag"},
+		{"2025-04-20", "BB", "Ostersonntag"},
+		{"2025-04-20", "BY", ""},
+
+		// Fronleichnam only in states where it applies everywhere
+		{"2024-05-30", "BY", "Fronleichnam"},
+		{"2025-06-19", "HE", "Fronleichnam"},
+		{"2023-06-08", "SL", "Fronleichnam"},
+		{"2025-06-19", "SN", ""},
+		{"2025-06-19", "BE", ""},
+
+		// Buß- und Bettag is the Wednesday before November 23rd
+		{"2017-11-22", "SN", "Buß- und Bettag"},
+		{"2022-11-16", "SN", "Buß- und Bettag"},
+		{"2024-11-20", "SN", "Buß- und Bettag"},
+		{"2025-11-19", "SN", "Buß- und Bettag"},
+		{"2024-11-20", "BY", ""},
+
+		// Reformationstag: eastern states always, northern states since
+		// 2018, everywhere in 2017
+		{"2016-10-31", "SN", "Reformationstag"},
+		{"2016-10-31", "NI", ""},
+		{"2017-10-31", "NI", "Reformationstag"},
+		{"2017-10-31", "BW", "Reformationstag"},
+		{"2018-10-31", "HH", "Reformationstag"},
+		{"2018-10-31", "BW", ""},
+		{"2024-10-31", "MV", "Reformationstag"},
+
+		// Frauentag in Berlin since 2019 and Mecklenburg-Vorpommern since 2023
+		{"2018-03-08", "BE", ""},
+		{"2019-03-08", "BE", "Internationaler Frauentag"},
+		{"2022-03-08", "MV", ""},
+		{"2023-03-08", "MV", "Internationaler Frauentag"},
+		{"2023-03-08", "BB", ""},
+
+		// One-off and regional fixed dates
+		{"2025-05-08", "BE", "Tag der Befreiung"},
+		{"2024-05-08", "BE", ""},
+		{"2024-01-06", "ST", "Heilige Drei Könige"},
+		{"2024-01-06", "NI", ""},
+		{"2024-08-15", "SL", "Mariä Himmelfahrt"},
+		{"2024-08-15", "BY", ""},
+		{"2018-09-20", "TH", ""},
+		{"2019-09-20", "TH", "Weltkindertag"},
+
+		// Nationwide
+		{"2025-10-03", "HB", "Tag der Deutschen Einheit"},
+		{"2025-12-26", "bw", "2. Weihnachtsfeiertag"},
+		{"2025-12-24", "BW", ""},
+	}
+
+	for _, tt := range tests {
+		h, ok := Lookup(date(tt.date), tt.state)
+		switch {
+		case tt.want == "" && ok:
+			t.Errorf("%s in %s: got %s, want no holiday", tt.date, tt.state, h.Name)
+		case tt.want != "" && (!ok || h.Name != tt.want):
+			t.Errorf("%s in %s: got %q (%v), want %s", tt.date, tt.state, h.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestForYear(t *testing.T) {
+	tests := []struct {
+		year  int
+		state string
+		want  int
+	}{
+		{2024, "BY", 12},
+		{2025, "BE", 11},
+		{2024, "SN", 11},
+		{2024, "NI", 10},
+		{2017, "NI", 10},
+		{2016, "NI", 9},
+	}
+
+	for _, tt := range tests {
+		got := ForYear(tt.year, tt.state)
+		if len(got) != tt.want {
+			t.Errorf("ForYear(%d, %s) has %d holidays, want %d: %v", tt.year, tt.state, len(got), tt.want, got)
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i].Date.Before(got[i-1].Date) {
+				t.Errorf("ForYear(%d, %s) is not ordered by date", tt.year, tt.state)
+			}
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	// Calendars name zones like Europe/Berlin; embed the database so that
+	// TZID works on systems without one, e.g. Windows
+	_ "time/tzdata"
+)
+
+// Event is a VEVENT of a calendar
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Categories  []string
+	Status      string
+
+	Start time.Time
+	End   time.Time
+	// AllDay events cover whole days; End is the day after the last one
+	AllDay bool
+
+	// Recurrence is the parsed RRULE, nil for single events
+	Recurrence *Rule
+	// ExDates are start times of excluded occurrences
+	ExDates []time.Time
+	// RecurrenceID marks an event that replaces one occurrence of the
+	// recurring event with the same UID
+	RecurrenceID time.Time
+}
+
+// Cancelled reports whether the event has been cancelled
+func (e Event) Cancelled() bool {
+	return strings.EqualFold(e.Status, "CANCELLED")
+}
+
+// ParseFile parses the calendar file at path
+func ParseFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open calendar: %w", err)
+	}
+	defer f.Close()
+
+	events, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return events, nil
+}
+
+// Parse reads the VEVENTs of an iCalendar stream. Other components, such
+// as VTODO or VALARM, are ignored.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var current *Event
+	var rrule string
+	// DURATION may come before DTSTART, so it is resolved at END:VEVENT
+	var length *duration
+	depth := 0 // nesting below the current VEVENT, e.g. VALARM
+
+	for n, line := range lines {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT") && current == nil:
+			current = &Event{}
+			rrule, length = "", nil
+			continue
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT") && current != nil && depth == 0:
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event %q has no DTSTART", n+1, current.Summary)
+			}
+			if rrule != "" {
+				rule, err := parseRule(rrule, current.Start.Location())
+				if err != nil {
+					return nil, fmt.Errorf("event %q: %w", current.Summary, err)
+				}
+				current.Recurrence = rule
+			}
+			if length != nil && current.End.IsZero() {
+				current.End = length.addTo(current.Start)
+			}
+			if current.End.IsZero() {
+				// RFC 5545: all-day events without an end last one day,
+				// timed events none
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *current)
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		if p.name == "BEGIN" {
+			depth++
+			continue
+		}
+		if p.name == "END" {
+			depth--
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+
+		switch p.name {
+		case "UID":
+			current.UID = p.value
+		case "SUMMARY":
+			current.Summary = unescapeText(p.value)
+		case "DESCRIPTION":
+			current.Description = unescapeText(p.value)
+		case "LOCATION":
+			current.Location = unescapeText(p.value)
+		case "STATUS":
+			current.Status = p.value
+		case "CATEGORIES":
+			for _, c := range splitText(p.value) {
+				if c = strings.TrimSpace(c); c != "" {
+					current.Categories = append(current.Categories, c)
+				}
+			}
+		case "DTSTART":
+			t, allDay, err := p.time()
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			current.Start, current.AllDay = t, allDay
+		case "DTEND":
+			t, _, err := p.time()
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			current.End = t
+		case "DURATION":
+			d, err := parseDuration(p.value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			length = &d
+		case "RRULE":
+			rrule = p.value
+		case "EXDATE":
+			for _, v := range strings.Split(p.value, ",") {
+				t, _, err := property{name: p.name, params: p.params, value: v}.time()
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", n+1, err)
+				}
+				current.ExDates = append(current.ExDates, t)
+			}
+		case "RECURRENCE-ID":
+			t, _, err := p.time()
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			current.RecurrenceID = t
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unterminated VEVENT %q", current.Summary)
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// property is a content line NAME;PARAM=VALUE:VALUE
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseProperty(line string) (property, error) {
+	// The value starts at the first colon outside of quoted parameters
+	quoted := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("invalid content line %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return p, nil
+}
+
+// time parses a DATE or DATE-TIME value. UTC times end in Z, TZID selects
+// a zone and times without either are local.
+func (p property) time() (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s date %q", p.name, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s time %q", p.name, value)
+		}
+		return t, false, nil
+	}
+
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = location(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s time %q", p.name, value)
+	}
+	return t, false, nil
+}
+
+// windowsZones maps the zone names Outlook exports to IANA names
+var windowsZones = map[string]string{
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Romance Standard Time":          "Europe/Paris",
+	"GMT Standard Time":              "Europe/London",
+	"UTC":                            "UTC",
+	"Coordinated Universal Time":     "UTC",
+	"Central European Standard Time": "Europe/Warsaw",
+}
+
+func location(tzid string) (*time.Location, error) {
+	// Some exporters prefix the zone with a path, e.g. /freeassociation.sourceforge.net/Europe/Berlin
+	if i := strings.Index(tzid, "/"); i == 0 {
+		parts := strings.Split(tzid, "/")
+		if len(parts) >= 2 {
+			tzid = strings.Join(parts[len(parts)-2:], "/")
+		}
+	}
+	if name, ok := windowsZones[tzid]; ok {
+		tzid = name
+	}
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", tzid)
+	}
+	return loc, nil
+}
+
+func unescapeText(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// splitText splits a list of TEXT values at unescaped commas
+func splitText(s string) []string {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			b.WriteByte(s[i])
+			b.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		if s[i] == ',' {
+			parts = append(parts, unescapeText(b.String()))
+			b.Reset()
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return append(parts, unescapeText(b.String()))
+}
+
+// duration is an iCalendar DURATION; days are kept apart from the clock
+// part so that they follow daylight saving time like dates do
+type duration struct {
+	days  int
+	clock time.Duration
+}
+
+func (d duration) addTo(t time.Time) time.Time {
+	return t.AddDate(0, 0, d.days).Add(d.clock)
+}
+
+// parseDuration parses values like P1D, PT1H30M or P1W
+func parseDuration(s string) (duration, error) {
+	value, ok := strings.CutPrefix(strings.TrimPrefix(s, "+"), "P")
+	if !ok {
+		return duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d duration
+	inTime := false
+	num := ""
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+		num = ""
+		switch {
+		case c == 'W' && !inTime:
+			d.days += 7 * n
+		case c == 'D' && !inTime:
+			d.days += n
+		case c == 'H' && inTime:
+			d.clock += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d.clock += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d.clock += time.Duration(n) * time.Second
+		default:
+			return duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	if num != "" {
+		return duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,66 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+		want string // end of the event
+	}{
+		{
+			"after DTSTART",
+			"DTSTART;TZID=Europe/Berlin:20240314T080000\nDURATION:PT1H30M",
+			"2024-03-14 09:30 +0100",
+		},
+		{
+			"before DTSTART",
+			"DURATION:PT1H30M\nDTSTART;TZID=Europe/Berlin:20240314T080000",
+			"2024-03-14 09:30 +0100",
+		},
+		{
+			// Days follow the local time across the switch to summer time
+			"days across DST",
+			"DURATION:P1D\nDTSTART;TZID=Europe/Berlin:20240330T120000",
+			"2024-03-31 12:00 +0200",
+		},
+		{
+			"DTEND wins",
+			"DURATION:PT1H\nDTSTART:20240314T080000Z\nDTEND:20240314T100000Z",
+			"2024-03-14 10:00 +0000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:1\n" + tt.ics + "\nEND:VEVENT\nEND:VCALENDAR\n"
+			events, err := Parse(strings.NewReader(cal))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			if got := events[0].End.Format("2006-01-02 15:04 -0700"); got != tt.want {
+				t.Errorf("end %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"no DTSTART":       "DURATION:PT1H",
+		"invalid duration": "DTSTART:20240314T080000Z\nDURATION:1H",
+		"invalid rule":     "DTSTART:20240314T080000Z\nRRULE:FREQ=HOURLY",
+	}
+	for name, body := range tests {
+		cal := "BEGIN:VCALENDAR\nBEGIN:VEVENT\n" + body + "\nEND:VEVENT\nEND:VCALENDAR\n"
+		if _, err := Parse(strings.NewReader(cal)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package ics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a recurrence rule (RRULE). The common frequencies DAILY, WEEKLY,
+// MONTHLY and YEARLY are supported with INTERVAL, COUNT, UNTIL, BYDAY,
+// BYMONTHDAY and BYMONTH.
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// WeekdayNum is a BYDAY value such as MO, 2TU or -1FR. N is 0 for every
+// such weekday of the period.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+func parseRule(value string, loc *time.Location) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			t, allDay, err := property{name: "UNTIL", value: val}.time()
+			if err != nil {
+				return nil, err
+			}
+			if allDay {
+				// A date includes the whole day
+				t = time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc)
+			}
+			rule.Until = t
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				code = strings.ToUpper(strings.TrimSpace(code))
+				if len(code) < 2 {
+					return nil, fmt.Errorf("invalid RRULE BYDAY %q", code)
+				}
+				day, ok := weekdayCodes[code[len(code)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid RRULE BYDAY %q", code)
+				}
+				n := 0
+				if prefix := code[:len(code)-2]; prefix != "" {
+					var err error
+					if n, err = strconv.Atoi(strings.TrimPrefix(prefix, "+")); err != nil || n == 0 {
+						return nil, fmt.Errorf("invalid RRULE BYDAY %q", code)
+					}
+				}
+				rule.ByDay = append(rule.ByDay, WeekdayNum{N: n, Day: day})
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid RRULE BYMONTHDAY %q", v)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid RRULE BYMONTH %q", v)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			// Weeks are assumed to start on Monday, the usual value
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %s", key)
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("RRULE without FREQ")
+	default:
+		return nil, fmt.Errorf("unsupported RRULE FREQ %s", rule.Freq)
+	}
+	return rule, nil
+}
+
+// Occurrence is a single instance of an event
+type Occurrence struct {
+	Event
+	// Start and End of this instance, shadowing those of the event
+	Start time.Time
+	End   time.Time
+}
+
+// Expand returns the occurrences of events that overlap [from, to),
+// sorted by start. Recurring events are expanded, excluded dates dropped
+// and modified instances replace the original ones. Cancelled events and
+// instances are left out.
+func Expand(events []Event, from, to time.Time) []Occurrence {
+	// Modified instances, keyed by UID and original start
+	overrides := make(map[string]bool)
+	for _, e := range events {
+		if !e.RecurrenceID.IsZero() {
+			overrides[e.UID+"|"+e.RecurrenceID.UTC().String()] = true
+		}
+	}
+
+	var result []Occurrence
+	add := func(e Event, start, end time.Time) {
+		if e.Cancelled() || !start.Before(to) {
+			return
+		}
+		// Events without duration still count on the day they start
+		if end.After(from) || (!end.After(start) && !start.Before(from)) {
+			result = append(result, Occurrence{Event: e, Start: start, End: end})
+		}
+	}
+
+	for _, e := range events {
+		if e.Recurrence == nil || !e.RecurrenceID.IsZero() {
+			add(e, e.Start, e.End)
+			continue
+		}
+
+		excluded := make(map[string]bool)
+		for _, t := range e.ExDates {
+			excluded[t.UTC().String()] = true
+		}
+
+		days := 0
+		for d := e.Start; d.Before(e.End) && e.AllDay; d = d.AddDate(0, 0, 1) {
+			days++
+		}
+		length := e.End.Sub(e.Start)
+
+		e.Recurrence.each(e.Start, to, func(start time.Time) {
+			if excluded[start.UTC().String()] {
+				return
+			}
+			if overrides[e.UID+"|"+start.UTC().String()] {
+				return
+			}
+			end := start.Add(length)
+			if e.AllDay {
+				end = start.AddDate(0, 0, days)
+			}
+			add(e, start, end)
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// maxPeriods stops runaway rules, e.g. a daily rule over centuries
+const maxPeriods = 100000
+
+// each calls fn with the start of every instance that begins before end,
+// starting with dtstart itself
+func (r *Rule) each(dtstart, end time.Time, fn func(time.Time)) {
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		candidates, periodStart := r.period(dtstart, period)
+		if !periodStart.Before(end) {
+			return
+		}
+		for _, t := range candidates {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return
+			}
+			if !t.Before(end) {
+				return
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return
+			}
+			fn(t)
+		}
+	}
+}
+
+// period returns the sorted instance starts of the n-th period of the rule
+// together with the beginning of that period
+func (r *Rule) period(dtstart time.Time, n int) ([]time.Time, time.Time) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+
+	var candidates []time.Time
+	var periodStart time.Time
+
+	switch r.Freq {
+	case "DAILY":
+		day := dtstart.AddDate(0, 0, n*r.Interval)
+		periodStart = at(day.Year(), day.Month(), day.Day())
+		if r.matchesMonth(day.Month()) && r.matchesWeekday(day.Weekday()) && r.matchesMonthDay(day) {
+			candidates = append(candidates, periodStart)
+		}
+
+	case "WEEKLY":
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := dtstart.AddDate(0, 0, -offset+7*n*r.Interval)
+		periodStart = at(monday.Year(), monday.Month(), monday.Day())
+		weekdays := []time.Weekday{dtstart.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = nil
+			for _, wd := range r.ByDay {
+				weekdays = append(weekdays, wd.Day)
+			}
+		}
+		for _, wd := range weekdays {
+			day := monday.AddDate(0, 0, (int(wd)+6)%7)
+			if r.matchesMonth(day.Month()) {
+				candidates = append(candidates, at(day.Year(), day.Month(), day.Day()))
+			}
+		}
+
+	case "MONTHLY":
+		first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, dtstart.Location())
+		periodStart = at(first.Year(), first.Month(), 1)
+		if r.matchesMonth(first.Month()) {
+			candidates = r.monthDays(dtstart, first.Year(), first.Month(), at)
+		}
+
+	case "YEARLY":
+		year := dtstart.Year() + n*r.Interval
+		periodStart = at(year, time.January, 1)
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{dtstart.Month()}
+		}
+		for _, month := range months {
+			candidates = append(candidates, r.monthDays(dtstart, year, month, at)...)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates, periodStart
+}
+
+// monthDays returns the instances within one month from BYMONTHDAY and
+// BYDAY, or the day of dtstart if neither is set
+func (r *Rule) monthDays(dtstart time.Time, year int, month time.Month, at func(int, time.Month, int) time.Time) []time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	seen := make(map[int]bool)
+	var days []int
+
+	addDay := func(d int) {
+		if d >= 1 && d <= last && !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d = last + 1 + d
+		}
+		addDay(d)
+	}
+
+	for _, wd := range r.ByDay {
+		var matches []int
+		for d := 1; d <= last; d++ {
+			if time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+				matches = append(matches, d)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			for _, d := range matches {
+				// Combined with BYMONTHDAY, BYDAY only filters
+				if len(r.ByMonthDay) == 0 {
+					addDay(d)
+				}
+			}
+		case wd.N > 0 && wd.N <= len(matches):
+			addDay(matches[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matches):
+			addDay(matches[len(matches)+wd.N])
+		}
+	}
+
+	if len(r.ByMonthDay) > 0 && len(r.ByDay) > 0 {
+		filtered := days[:0]
+		for _, d := range days {
+			if r.matchesWeekday(time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday()) {
+				filtered = append(filtered, d)
+			}
+		}
+		days = filtered
+	}
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		// Months without that day, e.g. the 31st, are skipped
+		addDay(dtstart.Day())
+	}
+
+	result := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		result = append(result, at(year, month, d))
+	}
+	return result
+}
+
+func (r *Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(day time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || last+1+d == t.Day() {
+			return true
+		}
+	}
+	return false
+}
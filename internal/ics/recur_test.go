@@ -0,0 +1,110 @@
+package ics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandFixtures(t *testing.T) {
+	tests := []struct {
+		file     string
+		from, to string
+		// Starts and ends of the occurrences as "start/end", in the zone of
+		// the event; all-day events as dates
+		want []string
+	}{
+		{
+			// BYDAY and COUNT; the week after the switch to summer time
+			// keeps the local time
+			"weekly-dst.ics", "2024-03-01", "2024-05-01",
+			[]string{
+				"2024-03-25 08:00 +0100/2024-03-25 14:00 +0100",
+				"2024-03-27 08:00 +0100/2024-03-27 14:00 +0100",
+				"2024-03-29 08:00 +0100/2024-03-29 14:00 +0100",
+				"2024-04-01 08:00 +0200/2024-04-01 14:00 +0200",
+				"2024-04-03 08:00 +0200/2024-04-03 14:00 +0200",
+			},
+		},
+		{
+			// UNTIL in UTC includes an instance starting at that moment;
+			// EXDATE lists two days, one after the switch to winter time
+			"daily-until.ics", "2024-10-01", "2024-11-01",
+			[]string{
+				"2024-10-24 09:30 +0200/2024-10-24 09:45 +0200",
+				"2024-10-25 09:30 +0200/2024-10-25 09:45 +0200",
+				"2024-10-27 09:30 +0100/2024-10-27 09:45 +0100",
+				"2024-10-28 09:30 +0100/2024-10-28 09:45 +0100",
+				"2024-10-30 09:30 +0100/2024-10-30 09:45 +0100",
+			},
+		},
+		{
+			// Only the part of the rule inside the range
+			"daily-until.ics", "2024-10-28", "2024-10-29",
+			[]string{
+				"2024-10-28 09:30 +0100/2024-10-28 09:45 +0100",
+			},
+		},
+		{
+			// The last Friday of each month
+			"monthly-byday.ics", "2024-01-01", "2025-01-01",
+			[]string{
+				"2024-01-26 14:00 +0100/2024-01-26 15:00 +0100",
+				"2024-02-23 14:00 +0100/2024-02-23 15:00 +0100",
+				"2024-03-29 14:00 +0100/2024-03-29 15:00 +0100",
+				"2024-04-26 14:00 +0200/2024-04-26 15:00 +0200",
+			},
+		},
+		{
+			// INTERVAL with an UNTIL date, which includes that day
+			"allday-until.ics", "2024-03-01", "2024-05-01",
+			[]string{
+				"2024-03-11/2024-03-13",
+				"2024-03-25/2024-03-27",
+				"2024-04-08/2024-04-10",
+			},
+		},
+		{
+			// A single all-day event from Monday to Sunday
+			"allday-week.ics", "2024-06-01", "2024-07-01",
+			[]string{"2024-06-10/2024-06-17"},
+		},
+		{
+			// A moved and a cancelled instance
+			"overrides.ics", "2024-06-01", "2024-07-01",
+			[]string{
+				"2024-06-03 07:00 +0000/2024-06-03 08:00 +0000",
+				"2024-06-11 12:00 +0000/2024-06-11 13:00 +0000",
+				"2024-06-24 07:00 +0000/2024-06-24 08:00 +0000",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file+" "+tt.from, func(t *testing.T) {
+			events, err := ParseFile("testdata/" + tt.file)
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			from, _ := time.Parse("2006-01-02", tt.from)
+			to, _ := time.Parse("2006-01-02", tt.to)
+
+			var got []string
+			for _, o := range Expand(events, from, to) {
+				layout := "2006-01-02 15:04 -0700"
+				if o.AllDay {
+					layout = "2006-01-02"
+				}
+				got = append(got, o.Start.Format(layout)+"/"+o.End.Format(layout))
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %q, want %q", len(got), got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
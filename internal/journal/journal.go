@@ -0,0 +1,166 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// Record states
+const (
+	StatusPending  = "pending" // snapshot taken, mutation not finished
+	StatusDone     = "done"    // mutation finished successfully
+	StatusFailed   = "failed"  // mutation returned an error, After shows what happened
+	StatusNoChange = "no_change"
+)
+
+// Record describes one mutation of the entries of a single day
+type Record struct {
+	ID        int                     `json:"id"`
+	Time      time.Time               `json:"time"`
+	Operation string                  `json:"operation"`
+	Date      string                  `json:"date"` // YYYY-MM-DD
+	Status    string                  `json:"status"`
+	Before    []azubiheft.ReportEntry `json:"before"`
+	After     []azubiheft.ReportEntry `json:"after,omitempty"`
+	Undoes    int                     `json:"undoes,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+}
+
+// Journal is an append-only log of entry snapshots stored as JSON lines.
+// A record is appended once before the mutation and again with the same ID
+// when it completes; readers keep the latest line per ID.
+type Journal struct {
+	path   string
+	mutex  sync.Mutex
+	nextID int
+}
+
+// New creates a journal persisted at path
+func New(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Begin appends a pending record and assigns its ID
+func (j *Journal) Begin(record Record) (Record, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.nextID == 0 {
+		records, err := j.read()
+		if err != nil {
+			return Record{}, err
+		}
+		j.nextID = 1
+		for _, r := range records {
+			if r.ID >= j.nextID {
+				j.nextID = r.ID + 1
+			}
+		}
+	}
+
+	record.ID = j.nextID
+	record.Time = time.Now()
+	record.Status = StatusPending
+	if err := j.append(record); err != nil {
+		return Record{}, err
+	}
+	j.nextID++
+	return record, nil
+}
+
+// Complete appends the final state of a record started with Begin
+func (j *Journal) Complete(record Record) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.append(record)
+}
+
+// Records returns the latest state of every record, oldest first
+func (j *Journal) Records() ([]Record, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.read()
+}
+
+// Get returns the latest state of the record with the given ID
+func (j *Journal) Get(id int) (Record, error) {
+	records, err := j.Records()
+	if err != nil {
+		return Record{}, err
+	}
+	for _, r := range records {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return Record{}, fmt.Errorf("journal entry %d not found", id)
+}
+
+func (j *Journal) append(record Record) error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal record: %w", err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return f.Sync()
+}
+
+func (j *Journal) read() ([]Record, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var order []int
+	latest := make(map[int]Record)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// A torn last line after a crash must not make the journal unusable
+			continue
+		}
+		if _, seen := latest[r.ID]; !seen {
+			order = append(order, r.ID)
+		}
+		latest[r.ID] = r
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	records := make([]Record, 0, len(order))
+	for _, id := range order {
+		records = append(records, latest[id])
+	}
+	return records, nil
+}
@@ -0,0 +1,123 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "journal.jsonl")
+	j := New(path)
+
+	before := []azubiheft.ReportEntry{{Seq: "1", Type: "Betrieb", Duration: "08:00", Text: "Support"}}
+	first, err := j.Begin(Record{Operation: "write_report", Date: "2024-05-06", Before: before})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if first.ID != 1 || first.Status != StatusPending || first.Time.IsZero() {
+		t.Errorf("Begin() = %+v, want ID 1, pending and a time", first)
+	}
+
+	second, err := j.Begin(Record{Operation: "delete_report", Date: "2024-05-07"})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if second.ID != 2 {
+		t.Errorf("second ID = %d, want 2", second.ID)
+	}
+
+	first.Status = StatusDone
+	first.After = append(before, azubiheft.ReportEntry{Seq: "2", Type: "Schule", Duration: "02:00", Text: "Mathe"})
+	if err := j.Complete(first); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	// Every call appends a line; readers keep the latest per ID in the
+	// order the IDs first appeared
+	lines := countLines(t, path)
+	if lines != 3 {
+		t.Errorf("journal has %d lines, want 3", lines)
+	}
+	records, err := j.Records()
+	if err != nil {
+		t.Fatalf("Records: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != 1 || records[1].ID != 2 {
+		t.Fatalf("Records() = %+v, want IDs 1 and 2", records)
+	}
+	if records[0].Status != StatusDone || !reflect.DeepEqual(records[0].After, first.After) {
+		t.Errorf("record 1 = %+v, want the completed state", records[0])
+	}
+	if records[1].Status != StatusPending {
+		t.Errorf("record 2 status = %q, want pending", records[1].Status)
+	}
+
+	got, err := j.Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !reflect.DeepEqual(got.Before, before) {
+		t.Errorf("Get(1).Before = %+v, want %+v", got.Before, before)
+	}
+	if _, err := j.Get(3); err == nil {
+		t.Error("Get(3) found a record that does not exist")
+	}
+
+	// A journal opened again continues the IDs of the file
+	third, err := New(path).Begin(Record{Operation: "undo", Undoes: 1})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if third.ID != 3 {
+		t.Errorf("ID after reopening = %d, want 3", third.ID)
+	}
+}
+
+func TestJournalSkipsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j := New(path)
+	if _, err := j.Begin(Record{Operation: "write_report", Date: "2024-05-06"}); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":2,"operation":"wri`)
+	f.Close()
+
+	records, err := New(path).Records()
+	if err != nil {
+		t.Fatalf("Records: %v", err)
+	}
+	if len(records) != 1 || records[0].ID != 1 {
+		t.Errorf("Records() = %+v, want only record 1", records)
+	}
+}
+
+func TestJournalMissingFile(t *testing.T) {
+	records, err := New(filepath.Join(t.TempDir(), "journal.jsonl")).Records()
+	if err != nil || len(records) != 0 {
+		t.Errorf("Records() = %+v, %v, want an empty journal", records, err)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, c := range data {
+		if c == '\n' {
+			n++
+		}
+	}
+	return n
+}
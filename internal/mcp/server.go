@@ -13,13 +13,20 @@ import (
 // ToolHandler is a function that handles tool execution
 type ToolHandler func(ctx context.Context, params map[string]interface{}) (string, error)
 
+// Middleware wraps the handler of a tool call. It is applied to every
+// tools/call request before the tool's handler runs.
+type Middleware func(toolName string, next ToolHandler) ToolHandler
+
 // Server represents an MCP server
 type Server struct {
-	name     string
-	version  string
-	tools    map[string]Tool
-	handlers map[string]ToolHandler
-	logger   *log.Logger
+	name        string
+	version     string
+	tools       map[string]Tool
+	handlers    map[string]ToolHandler
+	middlewares []Middleware
+	mutating    map[string]bool
+	readOnly    bool
+	logger      *log.Logger
 }
 
 // NewServer creates a new MCP server
@@ -32,6 +39,7 @@ func NewServer(name, version string, logger *log.Logger) *Server {
 		version:  version,
 		tools:    make(map[string]Tool),
 		handlers: make(map[string]ToolHandler),
+		mutating: make(map[string]bool),
 		logger:   logger,
 	}
 }
@@ -47,6 +55,68 @@ func (s *Server) RegisterTool(name, description string, inputSchema map[string]i
 	s.handlers[name] = handler
 }
 
+// RegisterMutatingTool registers a tool that changes remote state. In
+// read-only mode the tool is left out entirely.
+func (s *Server) RegisterMutatingTool(name, description string, inputSchema map[string]interface{}, handler ToolHandler) {
+	if s.readOnly {
+		s.logger.Printf("Read-only mode: not registering %s", name)
+		return
+	}
+	s.RegisterTool(name, description, inputSchema, handler)
+	s.mutating[name] = true
+}
+
+// RegisterOptionallyMutatingTool registers a tool that changes remote state
+// only for some arguments, such as target "report", and otherwise works
+// locally. It is mutating for the middlewares but stays available in
+// read-only mode, where the client refuses the requests that would change
+// the account.
+func (s *Server) RegisterOptionallyMutatingTool(name, description string, inputSchema map[string]interface{}, handler ToolHandler) {
+	s.RegisterTool(name, description, inputSchema, handler)
+	s.mutating[name] = true
+}
+
+// SetReadOnly enables read-only mode. It must be called before tools are
+// registered.
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
+
+// IsMutating reports whether a tool was registered as mutating
+func (s *Server) IsMutating(name string) bool {
+	return s.mutating[name]
+}
+
+// Use adds a middleware. Middlewares run in the order they were added, the
+// first one being the outermost.
+func (s *Server) Use(mw Middleware) {
+	s.middlewares = append(s.middlewares, mw)
+}
+
+// Handle wraps a handler that runs outside of tools/call, such as a
+// command line command, in the middlewares under the given tool name, so
+// that it is audited and checked like a tool call. A mutating handler is
+// refused in read-only mode.
+func (s *Server) Handle(toolName string, mutating bool, handler ToolHandler) ToolHandler {
+	if mutating {
+		if s.readOnly {
+			return func(ctx context.Context, params map[string]interface{}) (string, error) {
+				return "", fmt.Errorf("%s changes the account and is disabled in read-only mode", toolName)
+			}
+		}
+		s.mutating[toolName] = true
+	}
+	return s.wrap(toolName, handler)
+}
+
+// wrap applies the middlewares to a handler
+func (s *Server) wrap(toolName string, handler ToolHandler) ToolHandler {
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](toolName, handler)
+	}
+	return handler
+}
+
 // Serve starts the server and handles stdio communication
 func (s *Server) Serve() error {
 	reader := bufio.NewReader(os.Stdin)
@@ -141,7 +211,7 @@ func (s *Server) handleToolsCall(ctx context.Context, req JSONRPCRequest) {
 	}
 
 	// Execute handler
-	result, err := handler(ctx, args)
+	result, err := s.wrap(toolName, handler)(ctx, args)
 	if err != nil {
 		s.logger.Printf("Tool execution error (%s): %v", toolName, err)
 		s.sendResult(req.ID, ToolResult{
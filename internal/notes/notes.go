@@ -0,0 +1,307 @@
+package notes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A journal folder holds one Markdown file per day, named YYYY-MM-DD.md.
+// Each entry starts with a heading naming its type and duration:
+//
+//	## Betrieb (06:00)
+//	Text of the entry
+//
+//	## Schule (02:00)
+//	Text of the entry
+//
+// A file without such headings is a single entry whose type and duration
+// are given in the front matter:
+//
+//	---
+//	type: Betrieb
+//	duration: 08:00
+//	---
+//	Text of the entry
+//
+// Text before the first entry heading, such as a title, is not part of
+// any entry and is kept when a file is rewritten.
+
+// Entry is a report entry of a note. Type is a subject name or ID.
+type Entry struct {
+	Type     string
+	Duration string
+	Text     string
+}
+
+// StateFile is the name of the file that records the last sync
+const StateFile = ".azubiheft-sync.json"
+
+var (
+	headingRe  = regexp.MustCompile(`^#{1,6}\s+(.+?)\s+\((\d{1,2}:\d{2})\)\s*$`)
+	durationRe = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+	fileNameRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\.md$`)
+)
+
+// FileName returns the name of the note of date
+func FileName(date time.Time) string {
+	return date.Format("2006-01-02") + ".md"
+}
+
+// Dates lists the days that have a note in dir
+func Dates(dir string) ([]time.Time, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes folder: %w", err)
+	}
+
+	var dates []time.Time
+	for _, f := range files {
+		m := fileNameRe.FindStringSubmatch(f.Name())
+		if m == nil || f.IsDir() {
+			continue
+		}
+		if date, err := time.Parse("2006-01-02", m[1]); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+// Read parses the note of date in dir. It returns nil entries and no
+// error if there is no note.
+func Read(dir string, date time.Time) ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName(date)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note: %w", err)
+	}
+
+	entries, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName(date), err)
+	}
+	return entries, nil
+}
+
+// Parse reads the entries of a note
+func Parse(content string) ([]Entry, error) {
+	frontMatter, body := splitFrontMatter(content)
+
+	var entries []Entry
+	var text []string
+	flush := func() {
+		if len(entries) > 0 {
+			entries[len(entries)-1].Text = strings.TrimSpace(strings.Join(text, "\n"))
+		}
+		text = nil
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			entries = append(entries, Entry{Type: m[1], Duration: padDuration(m[2])})
+			continue
+		}
+		text = append(text, strings.TrimRight(line, " \t"))
+	}
+	flush()
+
+	if len(entries) > 0 {
+		return entries, nil
+	}
+
+	entryType := frontMatter["type"]
+	duration := frontMatter["duration"]
+	text = strings.Split(strings.TrimSpace(body), "\n")
+	if entryType == "" && duration == "" && strings.TrimSpace(body) == "" {
+		return nil, nil
+	}
+	if entryType == "" || duration == "" {
+		return nil, fmt.Errorf("no entry headings like \"## Betrieb (08:00)\" and no type and duration in the front matter")
+	}
+	if !durationRe.MatchString(duration) {
+		return nil, fmt.Errorf("invalid duration %q, use HH:MM", duration)
+	}
+	return []Entry{{Type: entryType, Duration: padDuration(duration), Text: strings.TrimSpace(strings.Join(text, "\n"))}}, nil
+}
+
+func padDuration(d string) string {
+	if len(d) == 4 {
+		return "0" + d
+	}
+	return d
+}
+
+// splitFrontMatter separates a leading --- block of key: value lines
+func splitFrontMatter(content string) (map[string]string, string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	values := make(map[string]string)
+	if !strings.HasPrefix(content, "---\n") {
+		return values, content
+	}
+
+	end := strings.Index(content[4:], "\n---")
+	if end < 0 {
+		return values, content
+	}
+	block := content[4 : 4+end]
+	body := strings.TrimPrefix(content[4+end+4:], "\n")
+
+	for _, line := range strings.Split(block, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return values, body
+}
+
+// Write stores entries as the note of date. The front matter and any text
+// before the first entry heading of an existing note are kept.
+func Write(dir string, date time.Time, entries []Entry) error {
+	path := filepath.Join(dir, FileName(date))
+
+	preamble := ""
+	if data, err := os.ReadFile(path); err == nil {
+		preamble = keptPreamble(string(data))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString(preamble)
+	for i, e := range entries {
+		if i > 0 || preamble != "" {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s (%s)\n\n%s\n", e.Type, e.Duration, strings.TrimSpace(e.Text))
+	}
+	return writeFile(path, []byte(b.String()))
+}
+
+// keptPreamble returns the part of a note that Write keeps: everything
+// before the first entry heading, without the type and duration of the
+// front matter, which headings replace
+func keptPreamble(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	var kept []string
+	inFrontMatter := len(lines) > 0 && lines[0] == "---"
+	hasHeadings := false
+	for _, line := range lines {
+		if headingRe.MatchString(line) {
+			hasHeadings = true
+			break
+		}
+	}
+
+	for i, line := range lines {
+		if inFrontMatter {
+			if i > 0 && line == "---" {
+				inFrontMatter = false
+				kept = append(kept, line)
+				if !hasHeadings {
+					// The body was the single entry
+					break
+				}
+				continue
+			}
+			key, _, _ := strings.Cut(line, ":")
+			key = strings.ToLower(strings.TrimSpace(key))
+			if i > 0 && (key == "type" || key == "duration") {
+				continue
+			}
+			kept = append(kept, line)
+			continue
+		}
+		if !hasHeadings || headingRe.MatchString(line) {
+			break
+		}
+		kept = append(kept, line)
+	}
+
+	// A front matter without keys left is dropped
+	if len(kept) == 2 && kept[0] == "---" && kept[1] == "---" {
+		kept = nil
+	}
+	preamble := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if preamble == "" {
+		return ""
+	}
+	return preamble + "\n"
+}
+
+// Hash identifies a set of entries independent of their order
+func Hash(entries []Entry) string {
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, strings.Join([]string{e.Type, e.Duration, strings.TrimSpace(e.Text)}, "\x00"))
+	}
+	sort.Strings(keys)
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\x01")))
+	return hex.EncodeToString(sum[:])
+}
+
+// State records the hash of each day's entries at the last sync, when the
+// note and the report were equal
+type State struct {
+	Version int               `json:"version"`
+	Days    map[string]string `json:"days"`
+}
+
+// LoadState reads the sync state of dir
+func LoadState(dir string) (*State, error) {
+	state := &State{Version: 1, Days: make(map[string]string)}
+
+	data, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if state.Days == nil {
+		state.Days = make(map[string]string)
+	}
+	return state, nil
+}
+
+// Save writes the sync state to dir
+func (s *State) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+	return writeFile(filepath.Join(dir, StateFile), append(data, '\n'))
+}
+
+// writeFile replaces path atomically so that an editor never sees a
+// partly written file
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
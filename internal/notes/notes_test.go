@@ -0,0 +1,213 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Entry
+		wantErr bool
+	}{
+		{
+			name:    "headings",
+			content: "## Betrieb (06:00)\nSupport\n\n## Schule (2:00)\nMathe\n- Brüche\n",
+			want: []Entry{
+				{Type: "Betrieb", Duration: "06:00", Text: "Support"},
+				{Type: "Schule", Duration: "02:00", Text: "Mathe\n- Brüche"},
+			},
+		},
+		{
+			name:    "title before the first heading",
+			content: "# Montag\n\nNotizen\n\n### Betrieb (08:00)\nSupport   \n",
+			want:    []Entry{{Type: "Betrieb", Duration: "08:00", Text: "Support"}},
+		},
+		{
+			name:    "front matter",
+			content: "---\ntype: Betrieb\nduration: \"8:00\"\n---\nSupport\n\nDeployment\n",
+			want:    []Entry{{Type: "Betrieb", Duration: "08:00", Text: "Support\n\nDeployment"}},
+		},
+		{
+			name:    "headings win over the front matter",
+			content: "---\ntype: Schule\nduration: 01:00\ntags: arbeit\n---\n## Betrieb (04:00)\nSupport\n",
+			want:    []Entry{{Type: "Betrieb", Duration: "04:00", Text: "Support"}},
+		},
+		{
+			name:    "CRLF",
+			content: "---\r\ntags: x\r\n---\r\n## Betrieb (06:00)\r\nSupport\r\n\r\n## Schule (02:00)\r\nMathe\r\n",
+			want: []Entry{
+				{Type: "Betrieb", Duration: "06:00", Text: "Support"},
+				{Type: "Schule", Duration: "02:00", Text: "Mathe"},
+			},
+		},
+		{
+			name:    "CRLF front matter",
+			content: "---\r\ntype: Betrieb\r\nduration: 08:00\r\n---\r\nSupport\r\n",
+			want:    []Entry{{Type: "Betrieb", Duration: "08:00", Text: "Support"}},
+		},
+		{
+			name:    "heading without duration is text",
+			content: "---\ntype: Betrieb\nduration: 08:00\n---\n## Support\nTickets\n",
+			want:    []Entry{{Type: "Betrieb", Duration: "08:00", Text: "## Support\nTickets"}},
+		},
+		{name: "empty", content: "", want: nil},
+		{name: "empty front matter", content: "---\ntags: x\n---\n\n", want: nil},
+		{name: "text without type", content: "Support\n", wantErr: true},
+		{name: "missing duration", content: "---\ntype: Betrieb\n---\nSupport\n", wantErr: true},
+		{name: "invalid duration", content: "---\ntype: Betrieb\nduration: 8h\n---\nSupport\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeptPreamble(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no preamble",
+			content: "## Betrieb (08:00)\nSupport\n",
+			want:    "",
+		},
+		{
+			name:    "title",
+			content: "# Montag\n\nNotizen\n\n## Betrieb (08:00)\nSupport\n",
+			want:    "# Montag\n\nNotizen\n",
+		},
+		{
+			name:    "front matter and title",
+			content: "---\ntags: arbeit\n---\n# Montag\n## Betrieb (08:00)\nSupport\n",
+			want:    "---\ntags: arbeit\n---\n# Montag\n",
+		},
+		{
+			name:    "single entry drops type and duration",
+			content: "---\ntitle: Montag\ntype: Betrieb\nDuration: 08:00\n---\nSupport\n",
+			want:    "---\ntitle: Montag\n---\n",
+		},
+		{
+			name:    "front matter left empty is dropped",
+			content: "---\ntype: Betrieb\nduration: 08:00\n---\nSupport\n",
+			want:    "",
+		},
+		{
+			name:    "text without headings is an entry",
+			content: "Support\n",
+			want:    "",
+		},
+		{
+			name:    "CRLF",
+			content: "---\r\ntags: arbeit\r\n---\r\n# Montag\r\n## Betrieb (08:00)\r\nSupport\r\n",
+			want:    "---\ntags: arbeit\n---\n# Montag\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keptPreamble(tt.content); got != tt.want {
+				t.Errorf("keptPreamble() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	date := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Type: "Betrieb", Duration: "06:00", Text: "Support\n"},
+		{Type: "Schule", Duration: "02:00", Text: "Mathe"},
+	}
+	body := "## Betrieb (06:00)\n\nSupport\n\n## Schule (02:00)\n\nMathe\n"
+
+	tests := []struct {
+		name     string
+		existing string // "" means no note
+		want     string
+	}{
+		{name: "new note", want: body},
+		{name: "replaces entries", existing: "## Betrieb (08:00)\nAlt\n", want: body},
+		{name: "keeps title", existing: "# Montag\n\n## Betrieb (08:00)\nAlt\n", want: "# Montag\n\n" + body},
+		{
+			name:     "keeps front matter",
+			existing: "---\r\ntitle: Montag\r\ntype: Betrieb\r\nduration: 08:00\r\n---\r\nAlt\r\n",
+			want:     "---\ntitle: Montag\n---\n\n" + body,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "2024-05-06.md")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Write(dir, date, entries); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("note = %q, want %q", data, tt.want)
+			}
+
+			// The written note reads back as the same entries
+			got, err := Read(dir, date)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if Hash(got) != Hash(entries) {
+				t.Errorf("Read() = %+v, want %+v", got, entries)
+			}
+			if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+				t.Errorf("temporary file left behind")
+			}
+		})
+	}
+}
+
+func TestDates(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"2024-05-06.md", "2024-05-07.md", "notes.md", "2024-05-08.txt", StateFile} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dates, err := Dates(dir)
+	if err != nil {
+		t.Fatalf("Dates: %v", err)
+	}
+	var got []string
+	for _, d := range dates {
+		got = append(got, d.Format("2006-01-02"))
+	}
+	if want := "2024-05-06 2024-05-07"; strings.Join(got, " ") != want {
+		t.Errorf("Dates() = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package pdf
+
+// Font is one of the standard fonts every PDF reader provides, so that no
+// font needs to be embedded
+type Font int
+
+// Standard fonts
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+func (f Font) resource() string {
+	if f == HelveticaBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+func (f Font) baseFont() string {
+	if f == HelveticaBold {
+		return "Helvetica-Bold"
+	}
+	return "Helvetica"
+}
+
+// Glyph widths of the characters 32 to 126 in 1/1000 of the font size,
+// from the Adobe font metrics
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// winAnsi maps the characters of WinAnsiEncoding outside Latin-1 to their
+// codes
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode converts text to WinAnsiEncoding. Characters the encoding lacks
+// become "?".
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsi[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// accented maps the accented Latin-1 letters to the letter whose width
+// they share
+var accented = map[byte]byte{}
+
+func init() {
+	for base, letters := range map[byte]string{
+		'A': "ÀÁÂÃÄÅ", 'C': "Ç", 'E': "ÈÉÊË", 'I': "ÌÍÎÏ", 'N': "Ñ", 'O': "ÒÓÔÕÖØ", 'U': "ÙÚÛÜ", 'Y': "Ý",
+		'a': "àáâãäå", 'c': "ç", 'e': "èéêë", 'i': "ìíîï", 'n': "ñ", 'o': "òóôõöø", 'u': "ùúûü", 'y': "ýÿ",
+		'S': "Š", 's': "š", 'Z': "Ž", 'z': "ž",
+	} {
+		for _, r := range letters {
+			accented[encode(string(r))[0]] = base
+		}
+	}
+}
+
+// width returns the width of an encoded character in 1/1000 of the font
+// size
+func (f Font) width(c byte) int {
+	widths := &helveticaWidths
+	if f == HelveticaBold {
+		widths = &helveticaBoldWidths
+	}
+	if base, ok := accented[c]; ok {
+		c = base
+	}
+	switch {
+	case c >= 32 && c <= 126:
+		return widths[c-32]
+	case c == 0xdf: // ß
+		return 611
+	case c == 0xc6: // Æ
+		return 1000
+	case c == 0xe6: // æ
+		return 889
+	case c == 0x85, c == 0x97, c == 0x89: // … — ‰
+		return 1000
+	case c == 0x95: // •
+		return 350
+	case c == 0xb0: // °
+		return 400
+	case c == 0x91, c == 0x92, c == 0x82: // ‘ ’ ‚
+		if f == HelveticaBold {
+			return 278
+		}
+		return 222
+	case c == 0x93, c == 0x94, c == 0x84: // “ ” „
+		if f == HelveticaBold {
+			return 500
+		}
+		return 333
+	}
+	return 556
+}
+
+// TextWidth returns the width of text in points
+func TextWidth(text string, font Font, size float64) float64 {
+	total := 0
+	for _, c := range encode(text) {
+		total += font.width(c)
+	}
+	return float64(total) * size / 1000
+}
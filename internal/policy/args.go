@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateArgKeys are the tool arguments that name a day a call may change
+var dateArgKeys = []string{"date", "from", "to", "target_date", "week_of"}
+
+// argDates returns the dates named by a call's arguments. For ranges the
+// result is ordered from, to.
+func argDates(args map[string]interface{}) ([]time.Time, error) {
+	var dates []time.Time
+	for _, key := range dateArgKeys {
+		value, ok := args[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s format, use YYYY-MM-DD", key)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// entryCount estimates how many entries a call touches
+func entryCount(args map[string]interface{}) int {
+	if ids, ok := args["draft_ids"].([]interface{}); ok {
+		return len(ids)
+	}
+	if count, ok := args["count"].(float64); ok {
+		return int(count)
+	}
+
+	perDay := 1
+	if entries, ok := args["entries"].([]interface{}); ok && len(entries) > 0 {
+		perDay = len(entries)
+	}
+
+	from, okFrom := args["from"].(string)
+	to, okTo := args["to"].(string)
+	if okFrom && okTo {
+		start, err1 := time.Parse("2006-01-02", from)
+		end, err2 := time.Parse("2006-01-02", to)
+		if err1 == nil && err2 == nil && !end.Before(start) {
+			days := int(end.Sub(start).Hours()/24) + 1
+			return days * perDay
+		}
+	}
+	return perDay
+}
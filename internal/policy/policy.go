@@ -0,0 +1,245 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/mcp"
+)
+
+// Policy restricts which tools may run and how much they may change
+type Policy struct {
+	// EnabledTools lists the only tools that may be called. Empty allows
+	// all tools not listed in DisabledTools.
+	EnabledTools  []string `json:"enabled_tools,omitempty"`
+	DisabledTools []string `json:"disabled_tools,omitempty"`
+
+	// MaxEntriesPerCall caps the number of entries a single mutating call
+	// may touch (days in a range times entries per day, draft IDs, ...)
+	MaxEntriesPerCall int `json:"max_entries_per_call,omitempty"`
+
+	// MaxAgeWeeks refuses mutations of dates more than this many weeks in
+	// the past
+	MaxAgeWeeks int `json:"max_age_weeks,omitempty"`
+	// LockedBefore refuses mutations of dates before this YYYY-MM-DD date
+	LockedBefore string `json:"locked_before,omitempty"`
+	// LockedWeeks lists signed weeks as ISO weeks (YYYY-Www) that must not
+	// be changed
+	LockedWeeks []string `json:"locked_weeks,omitempty"`
+
+	// RateLimits maps tool names to the allowed calls per minute. The key
+	// "*" applies to every tool without its own limit.
+	RateLimits map[string]int `json:"rate_limits,omitempty"`
+}
+
+// Load reads a policy from a JSON file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+
+	if p.LockedBefore != "" {
+		if _, err := time.Parse("2006-01-02", p.LockedBefore); err != nil {
+			return nil, fmt.Errorf("invalid locked_before, use YYYY-MM-DD: %w", err)
+		}
+	}
+	for _, week := range p.LockedWeeks {
+		var year, w int
+		if _, err := fmt.Sscanf(week, "%d-W%d", &year, &w); err != nil {
+			return nil, fmt.Errorf("invalid locked week %q, use YYYY-Www", week)
+		}
+	}
+
+	return &p, nil
+}
+
+// Engine enforces a Policy on tool calls
+type Engine struct {
+	policy     Policy
+	isMutating func(toolName string) bool
+	now        func() time.Time
+
+	mutex sync.Mutex
+	calls map[string][]time.Time
+}
+
+// NewEngine creates an engine. isMutating tells which tools change the
+// account; date and size limits only apply to those.
+func NewEngine(p Policy, isMutating func(toolName string) bool) *Engine {
+	return &Engine{
+		policy:     p,
+		isMutating: isMutating,
+		now:        time.Now,
+		calls:      make(map[string][]time.Time),
+	}
+}
+
+// Middleware rejects calls that violate the policy before their handler
+// runs. Mutating handlers get the engine in their context, so that they can
+// check the days and entries they resolve with CheckScope.
+func (e *Engine) Middleware(toolName string, next mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (string, error) {
+		if err := e.Check(toolName, args); err != nil {
+			return "", fmt.Errorf("blocked by policy: %w", err)
+		}
+		if e.isMutating(toolName) {
+			ctx = context.WithValue(ctx, engineKey{}, e)
+		}
+		return next(ctx, args)
+	}
+}
+
+type engineKey struct{}
+
+// CheckScope validates what a mutating call will change, as resolved by its
+// handler: the days it writes or deletes and the number of entries. Many
+// tools name their days only indirectly, through draft IDs, a year or a
+// file, so the arguments alone cannot be trusted to cover them. Calls
+// without a policy pass.
+func CheckScope(ctx context.Context, dates []time.Time, entries int) error {
+	e, ok := ctx.Value(engineKey{}).(*Engine)
+	if !ok {
+		return nil
+	}
+	if err := e.checkDateList(dates); err != nil {
+		return fmt.Errorf("blocked by policy: %w", err)
+	}
+	if max := e.policy.MaxEntriesPerCall; max > 0 && entries > max {
+		return fmt.Errorf("blocked by policy: call would touch %d entries, the limit is %d", entries, max)
+	}
+	return nil
+}
+
+// Check validates a single call against the policy and counts it towards
+// the rate limit
+func (e *Engine) Check(toolName string, args map[string]interface{}) error {
+	if !e.toolEnabled(toolName) {
+		return fmt.Errorf("tool %s is disabled", toolName)
+	}
+
+	if e.isMutating(toolName) {
+		if err := e.checkDates(args); err != nil {
+			return err
+		}
+		if max := e.policy.MaxEntriesPerCall; max > 0 {
+			if n := entryCount(args); n > max {
+				return fmt.Errorf("call would touch %d entries, the limit is %d", n, max)
+			}
+		}
+	}
+
+	return e.checkRate(toolName)
+}
+
+func (e *Engine) toolEnabled(toolName string) bool {
+	for _, name := range e.policy.DisabledTools {
+		if name == toolName {
+			return false
+		}
+	}
+	if len(e.policy.EnabledTools) == 0 {
+		return true
+	}
+	for _, name := range e.policy.EnabledTools {
+		if name == toolName {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) checkDates(args map[string]interface{}) error {
+	dates, err := argDates(args)
+	if err != nil {
+		return err
+	}
+	if err := e.checkDateList(dates); err != nil {
+		return err
+	}
+
+	// Checking both ends of a range covers the age limits, but locked
+	// weeks may lie in between
+	from, okFrom := args["from"].(string)
+	to, okTo := args["to"].(string)
+	if okFrom && okTo {
+		start, _ := time.Parse("2006-01-02", from)
+		end, _ := time.Parse("2006-01-02", to)
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			if err := e.checkLockedWeek(d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkDateList applies the age limits and locked weeks to each date
+func (e *Engine) checkDateList(dates []time.Time) error {
+	now := e.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	for _, date := range dates {
+		if weeks := e.policy.MaxAgeWeeks; weeks > 0 && date.Before(today.AddDate(0, 0, -7*weeks)) {
+			return fmt.Errorf("%s is older than %d weeks", date.Format("2006-01-02"), weeks)
+		}
+		if e.policy.LockedBefore != "" && date.Format("2006-01-02") < e.policy.LockedBefore {
+			return fmt.Errorf("dates before %s are locked", e.policy.LockedBefore)
+		}
+		if err := e.checkLockedWeek(date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) checkLockedWeek(date time.Time) error {
+	year, week := date.ISOWeek()
+	for _, locked := range e.policy.LockedWeeks {
+		var y, w int
+		if _, err := fmt.Sscanf(locked, "%d-W%d", &y, &w); err == nil && y == year && w == week {
+			return fmt.Errorf("week %d-W%02d is locked", year, week)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) checkRate(toolName string) error {
+	limit, ok := e.policy.RateLimits[toolName]
+	if !ok {
+		limit = e.policy.RateLimits["*"]
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := e.now()
+	windowStart := now.Add(-time.Minute)
+
+	recent := e.calls[toolName][:0]
+	for _, t := range e.calls[toolName] {
+		if t.After(windowStart) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= limit {
+		e.calls[toolName] = recent
+		return fmt.Errorf("rate limit of %d calls per minute for %s reached", limit, toolName)
+	}
+
+	e.calls[toolName] = append(recent, now)
+	return nil
+}
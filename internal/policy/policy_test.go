@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckScope(t *testing.T) {
+	p := Policy{
+		MaxEntriesPerCall: 5,
+		MaxAgeWeeks:       8,
+		LockedBefore:      "2025-03-01",
+		LockedWeeks:       []string{"2025-W10"},
+	}
+	engine := NewEngine(p, func(string) bool { return true })
+	engine.now = func() time.Time { return time.Date(2025, time.April, 2, 12, 0, 0, 0, time.UTC) }
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		dates   []time.Time
+		entries int
+		wantErr string
+	}{
+		{"allowed", []time.Time{day("2025-03-31"), day("2025-04-01")}, 2, ""},
+		{"too many entries", []time.Time{day("2025-03-31")}, 6, "limit is 5"},
+		{"locked week", []time.Time{day("2025-03-31"), day("2025-03-05")}, 2, "week 2025-W10 is locked"},
+		{"too old", []time.Time{day("2025-01-20")}, 1, "older than 8 weeks"},
+		{"locked before", []time.Time{day("2025-02-27")}, 1, "dates before 2025-03-01 are locked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Handlers reach the engine through the context the middleware
+			// passes on
+			handler := engine.Middleware("azubiheft_publish_drafts", func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return "", CheckScope(ctx, tt.dates, tt.entries)
+			})
+			_, err := handler(context.Background(), map[string]interface{}{})
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckScopeWithoutPolicy(t *testing.T) {
+	if err := CheckScope(context.Background(), []time.Time{{}}, 1000); err != nil {
+		t.Errorf("CheckScope without a policy: %v", err)
+	}
+}
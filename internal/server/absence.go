@@ -0,0 +1,169 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// absenceTypes maps accepted absence names to subject ID and entry text
+var absenceTypes = map[string]struct {
+	id   int
+	text string
+}{
+	"urlaub":          {azubiheft.SubjectUrlaub, "Urlaub"},
+	"vacation":        {azubiheft.SubjectUrlaub, "Urlaub"},
+	"arbeitsunfähig":  {azubiheft.SubjectArbeitsunfahig, "Arbeitsunfähig"},
+	"arbeitsunfaehig": {azubiheft.SubjectArbeitsunfahig, "Arbeitsunfähig"},
+	"sick":            {azubiheft.SubjectArbeitsunfahig, "Arbeitsunfähig"},
+}
+
+func (s *AzubiheftService) MarkAbsence(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	from, err := dateArg(args, "from")
+	if err != nil {
+		return "", err
+	}
+
+	to, err := dateArg(args, "to")
+	if err != nil {
+		return "", err
+	}
+	if to.Before(from) {
+		return "", fmt.Errorf("to must not be before from")
+	}
+
+	absenceName, _ := args["absence_type"].(string)
+	absence, ok := absenceTypes[strings.ToLower(absenceName)]
+	if !ok {
+		return "", fmt.Errorf("absence_type must be urlaub or arbeitsunfaehig")
+	}
+
+	message := absence.text
+	if val, ok := args["message"].(string); ok && val != "" {
+		message = val
+	}
+
+	timeSpent := s.config.StandardHours()
+	if val, ok := args["time_spent"].(string); ok {
+		if err := validateTimeSpent(val); err != nil {
+			return "", err
+		}
+		timeSpent = val
+	}
+
+	overwrite, _ := args["overwrite"].(bool)
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	weeks, err := session.GetWeeks()
+	if err != nil {
+		return "", fmt.Errorf("failed to get weeks: %w", err)
+	}
+
+	spec := azubiheft.EntrySpec{
+		Message:   message,
+		TimeSpent: timeSpent,
+		EntryType: absence.id,
+	}
+
+	var results []azubiheft.DayResult
+	var dates []time.Time
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if !isWorkday(date) {
+			continue
+		}
+		if reason, skip := s.dayOff(date); skip {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	// Entries are read once, so that overwrite deletes exactly the entries
+	// the confirmation was given for
+	existing := make(map[string][]azubiheft.ReportEntry)
+	var deletions []dayDeletion
+	entries := 0
+	targets := dates[:0]
+	for _, date := range dates {
+		current, err := session.FetchReport(date, true)
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+		existing[date.Format("2006-01-02")] = current
+		targets = append(targets, date)
+		entries++
+		if overwrite && len(otherEntries(current, absence.text)) > 0 {
+			deletions = append(deletions, dayDeletion{date: date, entries: current})
+			entries += len(current)
+		}
+	}
+
+	if err := policy.CheckScope(ctx, targets, entries); err != nil {
+		return "", err
+	}
+
+	if !session.IsDryRun() {
+		target := from.Format("2006-01-02") + ">" + to.Format("2006-01-02")
+		preview, err := s.confirmDeletions(ctx, args, "mark_absence", target, "replaced by "+absence.text, deletions)
+		if err != nil || preview != "" {
+			return preview, err
+		}
+	}
+
+	for _, date := range targets {
+		results = append(results, s.markAbsentDay(session, weeks, date, existing[date.Format("2006-01-02")], spec, absence.text, overwrite))
+	}
+	sortDayResults(results)
+
+	summary := fmt.Sprintf("%s from %s to %s:\n%s", absence.text,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), formatDayResults(results))
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
+
+func (s *AzubiheftService) markAbsentDay(session *azubiheft.Session, weeks []azubiheft.Week, date time.Time, existing []azubiheft.ReportEntry, spec azubiheft.EntrySpec, typeName string, overwrite bool) azubiheft.DayResult {
+	other := otherEntries(existing, typeName)
+	if len(existing) > 0 && len(other) == 0 {
+		return azubiheft.DayResult{Date: date, Status: azubiheft.DayPresent}
+	}
+	if len(existing) > 0 && !overwrite {
+		return azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped,
+			Reason: fmt.Sprintf("day has %d other entries, set overwrite to replace them", len(other))}
+	}
+
+	err := s.journaled(session, journal.Record{Operation: "mark_absence"}, date, func() error {
+		if err := session.DeleteEntries(date, existing); err != nil {
+			return err
+		}
+		return session.WriteEntries(date, weeks, []azubiheft.EntrySpec{spec})
+	})
+	if err != nil {
+		return azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()}
+	}
+	return azubiheft.DayResult{Date: date, Status: azubiheft.DayWritten}
+}
+
+// otherEntries returns the entries that are not of the absence type
+func otherEntries(entries []azubiheft.ReportEntry, typeName string) []azubiheft.ReportEntry {
+	var other []azubiheft.ReportEntry
+	for _, e := range entries {
+		if e.Type != typeName {
+			other = append(other, e)
+		}
+	}
+	return other
+}
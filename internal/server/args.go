@@ -0,0 +1,113 @@
+package azubiheftserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+)
+
+// dateArg parses a required YYYY-MM-DD argument
+func dateArg(args map[string]interface{}, key string) (time.Time, error) {
+	value, ok := args[key].(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s is required", key)
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s format, use YYYY-MM-DD: %w", key, err)
+	}
+	return date, nil
+}
+
+// optionalDateArg parses an optional YYYY-MM-DD argument. ok is false if
+// the argument is absent.
+func optionalDateArg(args map[string]interface{}, key string) (date time.Time, ok bool, err error) {
+	if _, present := args[key]; !present {
+		return time.Time{}, false, nil
+	}
+	date, err = dateArg(args, key)
+	return date, err == nil, err
+}
+
+// validateTimeSpent checks the HH:MM duration format used by Azubiheft
+func validateTimeSpent(timeSpent string) error {
+	return config.ValidateTimeSpent(timeSpent)
+}
+
+// stringListArg reads an argument that may be a single string or an array
+// of strings
+func stringListArg(args map[string]interface{}, key string) []string {
+	switch v := args[key].(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// weekdaysArg reads an optional list of weekday names
+func weekdaysArg(args map[string]interface{}, key string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range stringListArg(args, key) {
+		day, err := config.ParseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// entrySpecsArg reads an array of {message, time_spent, entry_type} objects
+func entrySpecsArg(args map[string]interface{}, key string) ([]azubiheft.EntrySpec, error) {
+	items, ok := args[key].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%s is required", key)
+	}
+
+	specs := make([]azubiheft.EntrySpec, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be an object", key, i)
+		}
+
+		message, ok := obj["message"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d].message is required", key, i)
+		}
+
+		timeSpent, ok := obj["time_spent"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d].time_spent is required", key, i)
+		}
+		if err := validateTimeSpent(timeSpent); err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", key, i, err)
+		}
+
+		entryType, ok := obj["entry_type"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d].entry_type is required", key, i)
+		}
+
+		specs = append(specs, azubiheft.EntrySpec{
+			Message:   message,
+			TimeSpent: timeSpent,
+			EntryType: int(entryType),
+		})
+	}
+	return specs, nil
+}
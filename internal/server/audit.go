@@ -0,0 +1,73 @@
+package azubiheftserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (s *AzubiheftService) QueryAudit(ctx context.Context, args map[string]interface{}) (string, error) {
+	if s.audit == nil {
+		return "", fmt.Errorf("audit log is disabled")
+	}
+
+	from, hasFrom, err := optionalDateArg(args, "from")
+	if err != nil {
+		return "", err
+	}
+
+	to, hasTo, err := optionalDateArg(args, "to")
+	if err != nil {
+		return "", err
+	}
+
+	// Audit entries carry local timestamps
+	if hasFrom {
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if hasTo {
+		to = time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond)
+	}
+
+	tool, _ := args["tool"].(string)
+
+	limit := 50
+	if val, ok := args["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+
+	entries, err := s.audit.Query(from, to, tool)
+	if err != nil {
+		return "", fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return "No audit entries found", nil
+	}
+
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d audit entries (oldest first):\n", len(entries))
+	for _, e := range entries {
+		argsJSON, _ := json.Marshal(e.Args)
+		fmt.Fprintf(&b, "- %s %s %s (%d ms, %d reads, %d mutations) args=%s",
+			e.Time.Format("2006-01-02 15:04:05"), e.Tool, e.Status, e.DurationMS, e.Reads, len(e.Mutations), argsJSON)
+		if e.Error != "" {
+			fmt.Fprintf(&b, " error=%q", e.Error)
+		}
+		b.WriteString("\n")
+		for _, m := range e.Mutations {
+			fmt.Fprintf(&b, "    %s %s -> %d", m.Method, m.URL, m.Status)
+			if m.Error != "" {
+				fmt.Fprintf(&b, " (%s)", m.Error)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,202 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/backup"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// ExportBackup writes every week, day and subject of the account to a
+// versioned JSON archive
+func (s *AzubiheftService) ExportBackup(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	session, err := s.getSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := backup.Export(session, func(done, total int) {
+		if done%10 == 0 || done == total {
+			s.logger.Printf("Exported %d of %d weeks", done, total)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := backup.Write(path, archive); err != nil {
+		return "", err
+	}
+
+	days := 0
+	for _, w := range archive.Weeks {
+		days += len(w.Days)
+	}
+	return fmt.Sprintf("Exported %d week(s), %d day(s) with %d entries and %d subject(s) to %s",
+		len(archive.Weeks), days, archive.Entries(), len(archive.Subjects), path), nil
+}
+
+// ImportBackup replays an archive into the account. Subjects are matched
+// by name and missing ones the imported days use are added; days that
+// already have entries are skipped, so an interrupted import can simply be
+// repeated.
+func (s *AzubiheftService) ImportBackup(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	from, hasFrom, err := optionalDateArg(args, "from")
+	if err != nil {
+		return "", err
+	}
+	to, hasTo, err := optionalDateArg(args, "to")
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := backup.Read(path)
+	if err != nil {
+		return "", err
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	subjectIDs, err := s.subjectIDs(session)
+	if err != nil {
+		return "", err
+	}
+
+	// Subjects of the archive that the account lacks. Static subjects
+	// exist in every account.
+	missing := make(map[string]bool)
+	for _, subject := range archive.Subjects {
+		if id, err := strconv.Atoi(subject.ID); err == nil && id <= azubiheft.SubjectFrei {
+			continue
+		}
+		if _, ok := subjectIDs[subject.Name]; !ok {
+			missing[subject.Name] = true
+		}
+	}
+
+	// The days are planned and checked against the policy before anything
+	// changes, so that a refused import adds no subjects either
+	var days []backup.Day
+	var dates []time.Time
+	var results []azubiheft.DayResult
+	entries := 0
+	var needed []string
+	adding := make(map[string]bool)
+	for _, week := range archive.Weeks {
+		for _, day := range week.Days {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				return "", fmt.Errorf("archive has an invalid date %q", day.Date)
+			}
+			if (hasFrom && date.Before(from)) || (hasTo && date.After(to)) {
+				continue
+			}
+
+			var reason string
+			for _, e := range day.Entries {
+				if _, ok := subjectIDs[e.Type]; ok {
+					continue
+				}
+				if !missing[e.Type] {
+					reason = fmt.Sprintf("unknown subject %q", e.Type)
+					break
+				}
+				if !adding[e.Type] {
+					adding[e.Type] = true
+					needed = append(needed, e.Type)
+				}
+			}
+			if reason != "" {
+				results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+				continue
+			}
+
+			days = append(days, day)
+			dates = append(dates, date)
+			entries += len(day.Entries)
+		}
+	}
+	if err := policy.CheckScope(ctx, dates, entries); err != nil {
+		return "", err
+	}
+
+	// Only subjects the imported days use are added
+	for _, name := range needed {
+		if err := session.AddSubject(name); err != nil {
+			return "", fmt.Errorf("failed to add subject %q: %w", name, err)
+		}
+	}
+	if len(needed) > 0 && !session.IsDryRun() {
+		if subjectIDs, err = s.subjectIDs(session); err != nil {
+			return "", err
+		}
+	}
+
+	planned := make(map[string][]azubiheft.EntrySpec)
+	dates = dates[:0]
+	for _, day := range days {
+		date, _ := time.Parse("2006-01-02", day.Date)
+
+		var specs []azubiheft.EntrySpec
+		var reason string
+		for _, e := range day.Entries {
+			id, ok := subjectIDs[e.Type]
+			if !ok {
+				reason = fmt.Sprintf("unknown subject %q", e.Type)
+				if session.IsDryRun() {
+					reason = fmt.Sprintf("subject %q is added first", e.Type)
+				}
+				break
+			}
+			specs = append(specs, azubiheft.EntrySpec{Message: e.Text, TimeSpent: e.Duration, EntryType: id})
+		}
+		if reason != "" {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+			continue
+		}
+
+		planned[day.Date] = specs
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	written, err := s.writeDays(ctx, session, "import_backup", dates, planned)
+	if err != nil {
+		return "", err
+	}
+	results = append(results, written...)
+	sortDayResults(results)
+
+	summary := fmt.Sprintf("Imported %s (exported %s):\n", path, archive.ExportedAt.Format("2006-01-02 15:04"))
+	if len(needed) > 0 {
+		summary += fmt.Sprintf("Added %d subject(s): %s\n", len(needed), strings.Join(needed, ", "))
+	}
+	summary += formatDayResults(results)
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
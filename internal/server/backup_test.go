@@ -0,0 +1,81 @@
+package azubiheftserver
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/backup"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// writeTestArchive writes an archive with a custom subject used on
+// 2024-05-06 and one no day uses
+func writeTestArchive(t *testing.T) string {
+	t.Helper()
+	archive := &backup.Archive{
+		Version:    backup.Version,
+		ExportedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Subjects: []azubiheft.Subject{
+			{ID: "1", Name: "Betrieb"},
+			{ID: "12", Name: "Projekt"},
+			{ID: "13", Name: "Unbenutzt"},
+		},
+		Weeks: []backup.Week{{
+			Week: azubiheft.Week{ID: "900", Year: 2024, Week: 19},
+			Days: []backup.Day{
+				{Date: "2024-05-06", Entries: []azubiheft.ReportEntry{{Type: "Projekt", Duration: "02:00", Text: "Planung"}}},
+				{Date: "2024-05-07", Entries: []azubiheft.ReportEntry{{Type: "Betrieb", Duration: "08:00", Text: "Support"}}},
+			},
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "backup.json")
+	if err := backup.Write(path, archive); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return path
+}
+
+func TestImportBackupAddsUsedSubjects(t *testing.T) {
+	s, site := newSiteService(t, &config.Config{})
+
+	out, err := s.ImportBackup(context.Background(), map[string]interface{}{
+		"session_id": "test",
+		"path":       writeTestArchive(t),
+	})
+	if err != nil {
+		t.Fatalf("ImportBackup: %v", err)
+	}
+	if !strings.Contains(out, "Added 1 subject(s): Projekt") {
+		t.Errorf("summary does not list the added subject:\n%s", out)
+	}
+
+	want := []string{"add subject Projekt", "write 20240506", "write 20240507"}
+	if got := site.mutations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mutations = %q, want %q", got, want)
+	}
+	if got := site.day("20240506"); !reflect.DeepEqual(got, []string{"Projekt 02:00 Planung"}) {
+		t.Errorf("2024-05-06 = %q", got)
+	}
+}
+
+func TestImportBackupRefusedByPolicyAddsNoSubjects(t *testing.T) {
+	s, site := newSiteService(t, &config.Config{})
+
+	engine := policy.NewEngine(policy.Policy{LockedBefore: "2024-05-07"}, func(string) bool { return true })
+	_, err := engine.Middleware("azubiheft_import_backup", s.ImportBackup)(context.Background(), map[string]interface{}{
+		"session_id": "test",
+		"path":       writeTestArchive(t),
+	})
+	if err == nil || !strings.Contains(err.Error(), "blocked by policy") {
+		t.Fatalf("err = %v, want a policy error", err)
+	}
+	if got := site.mutations(); len(got) != 0 {
+		t.Errorf("refused import changed the account: %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+)
+
+func (s *AzubiheftService) WriteRange(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	from, err := dateArg(args, "from")
+	if err != nil {
+		return "", err
+	}
+
+	to, err := dateArg(args, "to")
+	if err != nil {
+		return "", err
+	}
+
+	weekdays, err := weekdaysArg(args, "weekdays")
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := entrySpecsArg(args, "entries")
+	if err != nil {
+		return "", err
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := session.WriteRange(from, to, azubiheft.RangeOptions{
+		Weekdays: weekdays,
+		Skip:     s.dayOff,
+		Entries:  entries,
+		Wrap: func(date time.Time, write func() error) error {
+			return s.journaled(session, journal.Record{Operation: "write_range"}, date, write)
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to write range: %w", err)
+	}
+
+	summary := formatDayResults(results)
+	if session.IsDryRun() {
+		return formatDryRun(session, "Per-day plan:\n"+summary), nil
+	}
+	return summary, nil
+}
+
+// dayOff reports public holidays and configured days without entries
+func (s *AzubiheftService) dayOff(date time.Time) (string, bool) {
+	if holiday, ok := s.config.Holiday(date); ok {
+		return "public holiday: " + holiday.Name, true
+	}
+	if s.config.IsDayOff(date) {
+		return "day off", true
+	}
+	return "", false
+}
+
+// formatDayResults summarizes a per-day result list and explains how to
+// resume after failures
+func formatDayResults(results []azubiheft.DayResult) string {
+	counts := make(map[string]int)
+	var b strings.Builder
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(&b, "- %s %s: %s", r.Date.Format("2006-01-02"), r.Date.Weekday().String()[:3], r.Status)
+		if r.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", r.Reason)
+		}
+		b.WriteString("\n")
+	}
+
+	summary := fmt.Sprintf("%d written, %d already present, %d skipped, %d failed\n",
+		counts[azubiheft.DayWritten], counts[azubiheft.DayPresent], counts[azubiheft.DaySkipped], counts[azubiheft.DayFailed])
+	if counts[azubiheft.DayFailed] > 0 {
+		summary += "Repeat the same call to retry the failed days; entries that already exist are not written twice.\n"
+	}
+	return summary + b.String()
+}
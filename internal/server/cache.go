@@ -0,0 +1,65 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// cacheAccount names the cache of a login. It includes the site so that a
+// test server never shares pages with the real one.
+func (s *AzubiheftService) cacheAccount(username string) string {
+	baseURL := s.baseURL
+	if baseURL == "" {
+		baseURL = azubiheft.DefaultBaseURL
+	}
+	return strings.ToLower(username) + "@" + strings.TrimRight(baseURL, "/")
+}
+
+// CacheStatus describes the local report cache
+func (s *AzubiheftService) CacheStatus(ctx context.Context, args map[string]interface{}) (string, error) {
+	if s.cache == nil {
+		return "The report cache is disabled. Start the server with --cache-ttl to enable it.", nil
+	}
+
+	stats := s.cache.Stats()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Report cache (pages stay fresh for %s)\n", s.cache.TTL())
+	fmt.Fprintf(&b, "- %d account(s), %d with weeks, %d with subjects, %d day(s) cached\n",
+		stats.Accounts, stats.Weeks, stats.Subjects, stats.Days)
+	fmt.Fprintf(&b, "- %d fresh page(s)\n", stats.Fresh)
+	fmt.Fprintf(&b, "- Since start: %d hit(s), %d miss(es), %d refreshed page(s) changed, %d unchanged",
+		stats.Hits, stats.Misses, stats.Changed, stats.Unchanged)
+	return b.String(), nil
+}
+
+// ClearCache drops cached days of a date range, or the whole cache, so that
+// the next reads go to the site
+func (s *AzubiheftService) ClearCache(ctx context.Context, args map[string]interface{}) (string, error) {
+	if s.cache == nil {
+		return "The report cache is disabled, nothing to clear", nil
+	}
+
+	_, hasWeek := args["week_of"]
+	_, hasFrom := args["from"]
+	_, hasTo := args["to"]
+	if !hasWeek && !hasFrom && !hasTo {
+		if err := s.cache.Clear(); err != nil {
+			return "", err
+		}
+		return "Cleared the report cache", nil
+	}
+
+	from, to, err := rangeOrWeekArgs(args)
+	if err != nil {
+		return "", err
+	}
+	removed, err := s.cache.ClearDays(from, to)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Cleared %d cached day(s) from %s to %s",
+		removed, from.Format("2006-01-02"), to.Format("2006-01-02")), nil
+}
@@ -0,0 +1,252 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/ics"
+)
+
+func (s *AzubiheftService) ImportCalendar(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	from, to, err := rangeOrWeekArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := s.targetArg(args, targetDrafts)
+	if err != nil {
+		return "", err
+	}
+
+	defaultType := 0
+	if val, ok := args["default_entry_type"].(float64); ok {
+		defaultType = int(val)
+	}
+
+	events, err := ics.ParseFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Calendar times are compared in local time, dates of the range are
+	// whole local days
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	days := make(map[string]map[int]*calendarEntry)
+	var notes []string
+	for _, occ := range ics.Expand(events, start, end) {
+		text := strings.Join([]string{occ.Summary, occ.Description, occ.Location}, "\n")
+		rule, matched := entryRule(s.config.CalendarRules, text, occ.Categories)
+		if matched && rule.Ignore {
+			continue
+		}
+		entryType := rule.EntryType
+		if !matched {
+			entryType = defaultType
+		}
+		if entryType <= 0 {
+			notes = append(notes, fmt.Sprintf("%s %q: no matching calendar rule", occ.Start.Format("2006-01-02"), occ.Summary))
+			continue
+		}
+
+		for _, span := range daySpans(occ, start, end) {
+			minutes := span.minutes
+			if occ.AllDay {
+				minutes = durationMinutes(s.config.StandardHours())
+			}
+			if rule.TimeSpent != "" {
+				minutes = durationMinutes(rule.TimeSpent)
+			}
+
+			day := span.date.Format("2006-01-02")
+			if days[day] == nil {
+				days[day] = make(map[int]*calendarEntry)
+			}
+			entry := days[day][entryType]
+			if entry == nil {
+				entry = &calendarEntry{}
+				days[day][entryType] = entry
+			}
+			entry.minutes += minutes
+			entry.add(strings.TrimSpace(occ.Summary))
+		}
+	}
+
+	planned := make(map[string][]azubiheft.EntrySpec)
+	var dates []time.Time
+	var results []azubiheft.DayResult
+	for day, entries := range days {
+		date, _ := time.Parse("2006-01-02", day)
+		if !isWorkday(date) {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: "weekend"})
+			continue
+		}
+		if reason, skip := s.dayOff(date); skip {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+			continue
+		}
+
+		types := make([]int, 0, len(entries))
+		for t := range entries {
+			types = append(types, t)
+		}
+		sort.Ints(types)
+
+		for _, t := range types {
+			entry := entries[t]
+			if entry.minutes <= 0 {
+				notes = append(notes, fmt.Sprintf("%s %s: events without duration", day, strings.Join(entry.summaries, ", ")))
+				continue
+			}
+			planned[day] = append(planned[day], azubiheft.EntrySpec{
+				Message:   entry.message(),
+				TimeSpent: formatMinutes(entry.minutes),
+				EntryType: t,
+			})
+		}
+		if len(planned[day]) > 0 {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	if len(dates) == 0 && len(results) == 0 {
+		summary := fmt.Sprintf("No calendar events to import between %s and %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return summary + formatNotes(notes), nil
+	}
+
+	if target == targetDrafts {
+		results = append(results, s.createDrafts(dates, planned)...)
+		sortDayResults(results)
+		return fmt.Sprintf("Calendar imported as drafts:\n%s", formatDayResults(results)) + formatNotes(notes), nil
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	written, err := s.writeDays(ctx, session, "import_calendar", dates, planned)
+	if err != nil {
+		return "", err
+	}
+	results = append(results, written...)
+	sortDayResults(results)
+
+	summary := fmt.Sprintf("Calendar imported:\n%s", formatDayResults(results)) + formatNotes(notes)
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
+
+// entryRule returns the first rule matching an imported event or row
+func entryRule(rules []config.EntryRule, text string, categories []string) (config.EntryRule, bool) {
+	for _, rule := range rules {
+		if rule.Matches(text, categories) {
+			return rule, true
+		}
+	}
+	return config.EntryRule{}, false
+}
+
+// calendarEntry collects the events of one day and entry type
+type calendarEntry struct {
+	minutes   int
+	summaries []string
+}
+
+func (e *calendarEntry) add(summary string) {
+	if summary == "" {
+		return
+	}
+	for _, s := range e.summaries {
+		if s == summary {
+			return
+		}
+	}
+	e.summaries = append(e.summaries, summary)
+}
+
+// message lists the event titles, one per line if there are several
+func (e *calendarEntry) message() string {
+	switch len(e.summaries) {
+	case 0:
+		return defaultPlaceholder
+	case 1:
+		return e.summaries[0]
+	}
+	return "- " + strings.Join(e.summaries, "\n- ")
+}
+
+type daySpan struct {
+	date    time.Time
+	minutes int
+}
+
+// daySpans splits an occurrence into the local days it covers within
+// [start, end), with the minutes spent on each
+func daySpans(occ ics.Occurrence, start, end time.Time) []daySpan {
+	first := occ.Start.In(time.Local)
+	last := occ.End.In(time.Local)
+	if !last.After(first) {
+		// Events without duration belong to the day they start on
+		last = first
+	}
+
+	var spans []daySpan
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.Local); day.Before(end); {
+		next := day.AddDate(0, 0, 1)
+		if !day.Before(start) {
+			from, to := first, last
+			if from.Before(day) {
+				from = day
+			}
+			if to.After(next) {
+				to = next
+			}
+			minutes := 0
+			if to.After(from) {
+				minutes = int(to.Sub(from).Round(time.Minute) / time.Minute)
+			}
+			spans = append(spans, daySpan{
+				date:    time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+				minutes: minutes,
+			})
+		}
+		if !next.Before(last) {
+			break
+		}
+		day = next
+	}
+	return spans
+}
+
+// formatMinutes formats minutes as HH:MM, capped below a full day
+func formatMinutes(minutes int) string {
+	if minutes >= 24*60 {
+		minutes = 24*60 - 1
+	}
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func formatNotes(notes []string) string {
+	if len(notes) == 0 {
+		return ""
+	}
+	sort.Strings(notes)
+	return "\nNot imported:\n- " + strings.Join(notes, "\n- ") + "\n"
+}
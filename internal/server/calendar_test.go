@@ -0,0 +1,72 @@
+package azubiheftserver
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+)
+
+func TestImportCalendarSkipsWeekends(t *testing.T) {
+	cfg := &config.Config{
+		DailyHours:    "08:00",
+		CalendarRules: []config.EntryRule{{Category: "Schule", EntryType: 2}},
+	}
+	s := newTestService(t, cfg)
+
+	out, err := s.ImportCalendar(context.Background(), map[string]interface{}{
+		"path":    "testdata/allday-week.ics",
+		"week_of": "2024-06-12",
+		"target":  "drafts",
+	})
+	if err != nil {
+		t.Fatalf("ImportCalendar: %v", err)
+	}
+
+	drafts, err := s.drafts.List("", "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var days []string
+	for _, d := range drafts {
+		days = append(days, d.Date)
+		if d.EntryType != 2 || d.TimeSpent != "08:00" || d.Message != "Blockunterricht" {
+			t.Errorf("draft %+v, want Blockunterricht of type 2 for 08:00", d)
+		}
+	}
+	want := []string{"2024-06-10", "2024-06-11", "2024-06-12", "2024-06-13", "2024-06-14"}
+	if strings.Join(days, ",") != strings.Join(want, ",") {
+		t.Errorf("drafts on %v, want %v", days, want)
+	}
+	if n := strings.Count(out, "skipped (weekend)"); n != 2 {
+		t.Errorf("%d weekend days reported as skipped, want 2:\n%s", n, out)
+	}
+}
+
+func TestImportCalendarReadOnly(t *testing.T) {
+	cfg := &config.Config{
+		DailyHours:    "08:00",
+		CalendarRules: []config.EntryRule{{Category: "Schule", EntryType: 2}},
+	}
+	s := NewAzubiheftService(log.New(io.Discard, "", 0), "", "", Options{DataDir: t.TempDir(), Config: cfg, ReadOnly: true})
+	args := map[string]interface{}{
+		"path":    "testdata/allday-week.ics",
+		"week_of": "2024-06-12",
+	}
+
+	// Drafts never touch the account
+	if _, err := s.ImportCalendar(context.Background(), args); err != nil {
+		t.Fatalf("ImportCalendar: %v", err)
+	}
+	if drafts, _ := s.drafts.List("", ""); len(drafts) != 5 {
+		t.Errorf("%d drafts created, want 5", len(drafts))
+	}
+
+	args["target"] = "report"
+	if _, err := s.ImportCalendar(context.Background(), args); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("target report: err = %v, want a read-only error", err)
+	}
+}
@@ -0,0 +1,147 @@
+package azubiheftserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// confirmationTTL is how long a destructive preview stays valid
+const confirmationTTL = 5 * time.Minute
+
+// confirmations holds the tokens handed out by destructive tools. A token
+// is bound to the tool, its target and the exact set of affected items, so
+// it can only confirm the deletion the user actually saw.
+type confirmations struct {
+	mutex   sync.Mutex
+	pending map[string]pendingConfirmation
+}
+
+type pendingConfirmation struct {
+	binding string
+	expires time.Time
+}
+
+// issue returns a new token for the given tool, target and affected items
+func (c *confirmations) issue(tool, target string, items []string) (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to create confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.pending == nil {
+		c.pending = make(map[string]pendingConfirmation)
+	}
+	now := time.Now()
+	for t, p := range c.pending {
+		if now.After(p.expires) {
+			delete(c.pending, t)
+		}
+	}
+
+	c.pending[token] = pendingConfirmation{
+		binding: confirmationBinding(tool, target, items),
+		expires: now.Add(confirmationTTL),
+	}
+	return token, nil
+}
+
+// consume validates and invalidates a token. It fails if the token is
+// unknown, expired, or was issued for a different set of items.
+func (c *confirmations) consume(token, tool, target string, items []string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	p, ok := c.pending[token]
+	if !ok {
+		return fmt.Errorf("unknown or already used confirmation token")
+	}
+	delete(c.pending, token)
+
+	if time.Now().After(p.expires) {
+		return fmt.Errorf("confirmation token expired, request a new preview")
+	}
+	if p.binding != confirmationBinding(tool, target, items) {
+		return fmt.Errorf("the affected entries changed since the preview, request a new preview")
+	}
+	return nil
+}
+
+func confirmationBinding(tool, target string, items []string) string {
+	sorted := append([]string(nil), items...)
+	sort.Strings(sorted)
+	return tool + "|" + target + "|" + strings.Join(sorted, ",")
+}
+
+type commandLineKey struct{}
+
+// CommandLine marks calls from the command line, where a token cannot be
+// passed back. With confirmed set, deletions that need a confirmation go
+// ahead, as the user asked for them up front; otherwise they are only
+// previewed.
+func CommandLine(ctx context.Context, confirmed bool) context.Context {
+	return context.WithValue(ctx, commandLineKey{}, confirmed)
+}
+
+// dayDeletion lists the entries of a day a tool is about to delete
+type dayDeletion struct {
+	date    time.Time
+	entries []azubiheft.ReportEntry
+}
+
+// confirmDeletions is the two-step flow for tools that delete entries on
+// several days. Without a confirmation_token it returns a preview of the
+// entries and a token bound to them; the tool must stop and show it. With a
+// token it returns "" once the token matches exactly these entries. Calls
+// from the command line have no tokens; see CommandLine.
+func (s *AzubiheftService) confirmDeletions(ctx context.Context, args map[string]interface{}, tool, target, action string, days []dayDeletion) (string, error) {
+	var affected []string
+	for _, d := range days {
+		for _, e := range d.entries {
+			affected = append(affected, d.date.Format("2006-01-02")+"#"+e.Seq)
+		}
+	}
+	if len(affected) == 0 {
+		return "", nil
+	}
+
+	confirmed, commandLine := ctx.Value(commandLineKey{}).(bool)
+	if confirmed {
+		return "", nil
+	}
+	token, _ := args["confirmation_token"].(string)
+	if token != "" && !commandLine {
+		return "", s.confirmations.consume(token, tool, target, affected)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d entry(s) on %d day(s) will be deleted and %s:\n", len(affected), len(days), action)
+	for _, d := range days {
+		for _, e := range d.entries {
+			fmt.Fprintf(&b, "- %s [Seq %s] %s, %s: %s\n", d.date.Format("2006-01-02"), e.Seq, e.Type, e.Duration, strings.ReplaceAll(e.Text, "\n", " "))
+		}
+	}
+	if commandLine {
+		b.WriteString("Nothing has been changed yet. To confirm, run the command again with --yes.")
+		return b.String(), nil
+	}
+
+	token, err := s.confirmations.issue(tool, target, affected)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "Nothing has been changed yet. To confirm, call azubiheft_%s again with the same arguments and confirmation_token \"%s\" within %d minutes.",
+		tool, token, int(confirmationTTL.Minutes()))
+	return b.String(), nil
+}
@@ -0,0 +1,122 @@
+package azubiheftserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+)
+
+func TestConfirmations(t *testing.T) {
+	items := []string{"2024-05-06#1", "2024-05-07#2"}
+
+	tests := []struct {
+		name    string
+		tool    string
+		target  string
+		items   []string
+		wantErr string // "" means the token is accepted
+	}{
+		{name: "same binding", tool: "copy_week", target: "2024-05-06", items: items},
+		{name: "items in another order", tool: "copy_week", target: "2024-05-06", items: []string{"2024-05-07#2", "2024-05-06#1"}},
+		{name: "other tool", tool: "mark_absence", target: "2024-05-06", items: items, wantErr: "changed since the preview"},
+		{name: "other target", tool: "copy_week", target: "2024-05-13", items: items, wantErr: "changed since the preview"},
+		{name: "fewer items", tool: "copy_week", target: "2024-05-06", items: items[:1], wantErr: "changed since the preview"},
+		{name: "more items", tool: "copy_week", target: "2024-05-06", items: append([]string{"2024-05-08#3"}, items...), wantErr: "changed since the preview"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c confirmations
+			token, err := c.issue("copy_week", "2024-05-06", items)
+			if err != nil {
+				t.Fatalf("issue: %v", err)
+			}
+
+			err = c.consume(token, tt.tool, tt.target, tt.items)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("consume: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("consume() = %v, want an error containing %q", err, tt.wantErr)
+			}
+
+			// A token is used up by the first attempt, whatever its outcome
+			err = c.consume(token, "copy_week", "2024-05-06", items)
+			if err == nil || !strings.Contains(err.Error(), "already used") {
+				t.Errorf("reused token: consume() = %v", err)
+			}
+		})
+	}
+}
+
+func TestConfirmationExpiry(t *testing.T) {
+	var c confirmations
+	token, err := c.issue("delete_report", "2024-05-06", []string{"1"})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	p := c.pending[token]
+	p.expires = time.Now().Add(-time.Second)
+	c.pending[token] = p
+
+	if err := c.consume(token, "delete_report", "2024-05-06", []string{"1"}); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("consume() = %v, want an expiry error", err)
+	}
+
+	// Expired tokens are dropped when the next one is issued
+	expired, _ := c.issue("delete_report", "2024-05-06", []string{"1"})
+	p = c.pending[expired]
+	p.expires = time.Now().Add(-time.Second)
+	c.pending[expired] = p
+	if _, err := c.issue("delete_report", "2024-05-07", []string{"2"}); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, ok := c.pending[expired]; ok || len(c.pending) != 1 {
+		t.Errorf("expired token kept: %v", c.pending)
+	}
+}
+
+func TestConfirmDeletions(t *testing.T) {
+	s := newTestService(t, &config.Config{})
+	date := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	days := []dayDeletion{{date: date, entries: []azubiheft.ReportEntry{{Seq: "1", Type: "Betrieb", Duration: "08:00", Text: "Support"}}}}
+	ctx := context.Background()
+
+	if preview, err := s.confirmDeletions(ctx, map[string]interface{}{}, "copy_week", "x", "replaced", nil); preview != "" || err != nil {
+		t.Errorf("nothing to delete: confirmDeletions() = %q, %v", preview, err)
+	}
+
+	preview, err := s.confirmDeletions(ctx, map[string]interface{}{}, "copy_week", "x", "replaced", days)
+	if err != nil {
+		t.Fatalf("confirmDeletions: %v", err)
+	}
+	if !strings.Contains(preview, "2024-05-06 [Seq 1] Betrieb, 08:00: Support") {
+		t.Errorf("preview does not list the entry:\n%s", preview)
+	}
+	token := confirmationToken(t, preview)
+
+	// The entries changed since the preview
+	changed := []dayDeletion{{date: date, entries: append(days[0].entries, azubiheft.ReportEntry{Seq: "2"})}}
+	if _, err := s.confirmDeletions(ctx, map[string]interface{}{"confirmation_token": token}, "copy_week", "x", "replaced", changed); err == nil {
+		t.Error("token confirmed other entries than the preview showed")
+	}
+
+	preview, _ = s.confirmDeletions(ctx, map[string]interface{}{}, "copy_week", "x", "replaced", days)
+	args := map[string]interface{}{"confirmation_token": confirmationToken(t, preview)}
+	if preview, err := s.confirmDeletions(ctx, args, "copy_week", "x", "replaced", days); preview != "" || err != nil {
+		t.Errorf("confirmed: confirmDeletions() = %q, %v", preview, err)
+	}
+
+	// The command line has no tokens: --yes confirms up front, without it
+	// the preview asks for --yes
+	preview, err = s.confirmDeletions(CommandLine(ctx, false), args, "copy_week", "x", "replaced", days)
+	if err != nil || !strings.Contains(preview, "--yes") || strings.Contains(preview, "confirmation_token") {
+		t.Errorf("command line preview = %q, %v", preview, err)
+	}
+	if preview, err := s.confirmDeletions(CommandLine(ctx, true), map[string]interface{}{}, "copy_week", "x", "replaced", days); preview != "" || err != nil {
+		t.Errorf("command line with --yes: confirmDeletions() = %q, %v", preview, err)
+	}
+}
@@ -0,0 +1,194 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// Copy modes for days of the target week that already have entries
+const (
+	copyOverwrite = "overwrite"
+	copyAppend    = "append"
+	copySkip      = "skip"
+)
+
+const defaultPlaceholder = "TODO"
+
+func (s *AzubiheftService) CopyWeek(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	sourceDate, err := dateArg(args, "source_date")
+	if err != nil {
+		return "", err
+	}
+
+	targetDate, err := dateArg(args, "target_date")
+	if err != nil {
+		return "", err
+	}
+
+	mode := copySkip
+	if val, ok := args["mode"].(string); ok && val != "" {
+		mode = val
+	}
+	if mode != copyOverwrite && mode != copyAppend && mode != copySkip {
+		return "", fmt.Errorf("invalid mode %q, use overwrite, append or skip", mode)
+	}
+
+	structureOnly, _ := args["structure_only"].(bool)
+	placeholder := defaultPlaceholder
+	if val, ok := args["placeholder"].(string); ok && val != "" {
+		placeholder = val
+	}
+
+	sourceMonday := mondayOf(sourceDate)
+	targetMonday := mondayOf(targetDate)
+	if sourceMonday.Equal(targetMonday) {
+		return "", fmt.Errorf("source and target are the same week")
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	subjectIDs, err := s.subjectIDs(session)
+	if err != nil {
+		return "", err
+	}
+
+	weeks, err := session.GetWeeks()
+	if err != nil {
+		return "", fmt.Errorf("failed to get weeks: %w", err)
+	}
+
+	var results []azubiheft.DayResult
+	var dates []time.Time
+	planned := make(map[string][]azubiheft.EntrySpec)
+	entries := 0
+	for i := 0; i < 7; i++ {
+		sourceDay := sourceMonday.AddDate(0, 0, i)
+		targetDay := targetMonday.AddDate(0, 0, i)
+
+		source, err := session.GetReport(sourceDay, true)
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: targetDay, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+		if len(source) == 0 {
+			continue
+		}
+
+		specs, err := copySpecs(source, subjectIDs, structureOnly, placeholder)
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: targetDay, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+
+		dates = append(dates, targetDay)
+		planned[targetDay.Format("2006-01-02")] = specs
+		entries += len(specs)
+	}
+
+	// Entries of the target days are read once, so that overwrite deletes
+	// exactly the entries the confirmation was given for
+	existing := make(map[string][]azubiheft.ReportEntry)
+	var deletions []dayDeletion
+	targets := dates[:0]
+	for _, date := range dates {
+		current, err := session.FetchReport(date, true)
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+		existing[date.Format("2006-01-02")] = current
+		targets = append(targets, date)
+		if mode == copyOverwrite && len(current) > 0 {
+			deletions = append(deletions, dayDeletion{date: date, entries: current})
+			entries += len(current)
+		}
+	}
+
+	if err := policy.CheckScope(ctx, targets, entries); err != nil {
+		return "", err
+	}
+
+	if !session.IsDryRun() {
+		target := sourceMonday.Format("2006-01-02") + ">" + targetMonday.Format("2006-01-02")
+		preview, err := s.confirmDeletions(ctx, args, "copy_week", target, "replaced by the copied entries", deletions)
+		if err != nil || preview != "" {
+			return preview, err
+		}
+	}
+
+	for _, date := range targets {
+		day := date.Format("2006-01-02")
+		results = append(results, s.copyDay(session, weeks, date, existing[day], planned[day], mode))
+	}
+	sortDayResults(results)
+
+	if len(results) == 0 {
+		return fmt.Sprintf("The week of %s has no entries to copy", sourceMonday.Format("2006-01-02")), nil
+	}
+
+	summary := fmt.Sprintf("Copy of week %s to week %s (%s):\n%s",
+		sourceMonday.Format("2006-01-02"), targetMonday.Format("2006-01-02"), mode, formatDayResults(results))
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
+
+func (s *AzubiheftService) copyDay(session *azubiheft.Session, weeks []azubiheft.Week, date time.Time, existing []azubiheft.ReportEntry, specs []azubiheft.EntrySpec, mode string) azubiheft.DayResult {
+	if len(existing) > 0 && mode == copySkip {
+		return azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: "target day already has entries"}
+	}
+
+	err := s.journaled(session, journal.Record{Operation: "copy_week"}, date, func() error {
+		if mode == copyOverwrite {
+			if err := session.DeleteEntries(date, existing); err != nil {
+				return err
+			}
+		}
+		return session.WriteEntries(date, weeks, specs)
+	})
+	if err != nil {
+		return azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()}
+	}
+
+	return azubiheft.DayResult{Date: date, Status: azubiheft.DayWritten}
+}
+
+// copySpecs turns existing entries into entries to write elsewhere
+func copySpecs(entries []azubiheft.ReportEntry, subjectIDs map[string]int, structureOnly bool, placeholder string) ([]azubiheft.EntrySpec, error) {
+	specs := make([]azubiheft.EntrySpec, 0, len(entries))
+	for _, e := range entries {
+		typeID, ok := subjectIDs[e.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown entry type %q", e.Type)
+		}
+
+		message := e.Text
+		if structureOnly {
+			message = placeholder
+		}
+
+		specs = append(specs, azubiheft.EntrySpec{
+			Message:   message,
+			TimeSpent: e.Duration,
+			EntryType: typeID,
+		})
+	}
+	return specs, nil
+}
+
+// mondayOf returns the Monday of the ISO week containing date
+func mondayOf(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
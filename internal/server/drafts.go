@@ -0,0 +1,238 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/drafts"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+func (s *AzubiheftService) CreateDraft(ctx context.Context, args map[string]interface{}) (string, error) {
+	date, err := dateArg(args, "date")
+	if err != nil {
+		return "", err
+	}
+
+	message, ok := args["message"].(string)
+	if !ok {
+		return "", fmt.Errorf("message is required")
+	}
+
+	timeSpent, ok := args["time_spent"].(string)
+	if !ok {
+		return "", fmt.Errorf("time_spent is required")
+	}
+	if err := validateTimeSpent(timeSpent); err != nil {
+		return "", err
+	}
+
+	entryType, ok := args["entry_type"].(float64)
+	if !ok {
+		return "", fmt.Errorf("entry_type is required")
+	}
+
+	draft, err := s.drafts.Create(drafts.Draft{
+		Date:      date.Format("2006-01-02"),
+		Message:   message,
+		TimeSpent: timeSpent,
+		EntryType: int(entryType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create draft: %w", err)
+	}
+
+	result := fmt.Sprintf("Draft %s created for %s", draft.ID, draft.Date)
+	return result, nil
+}
+
+func (s *AzubiheftService) ListDrafts(ctx context.Context, args map[string]interface{}) (string, error) {
+	from, to, err := draftRange(args)
+	if err != nil {
+		return "", err
+	}
+
+	list, err := s.drafts.List(from, to)
+	if err != nil {
+		return "", fmt.Errorf("failed to list drafts: %w", err)
+	}
+
+	if len(list) == 0 {
+		return "No drafts found", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d draft(s):\n", len(list))
+	for _, d := range list {
+		b.WriteString(formatDraft(d))
+	}
+	return b.String(), nil
+}
+
+func (s *AzubiheftService) EditDraft(ctx context.Context, args map[string]interface{}) (string, error) {
+	draftID, ok := args["draft_id"].(string)
+	if !ok {
+		return "", fmt.Errorf("draft_id is required")
+	}
+
+	date, hasDate, err := optionalDateArg(args, "date")
+	if err != nil {
+		return "", err
+	}
+
+	timeSpent, hasTimeSpent := args["time_spent"].(string)
+	if hasTimeSpent {
+		if err := validateTimeSpent(timeSpent); err != nil {
+			return "", err
+		}
+	}
+
+	message, hasMessage := args["message"].(string)
+	entryType, hasEntryType := args["entry_type"].(float64)
+
+	draft, err := s.drafts.Update(draftID, func(d *drafts.Draft) {
+		if hasDate {
+			d.Date = date.Format("2006-01-02")
+		}
+		if hasMessage {
+			d.Message = message
+		}
+		if hasTimeSpent {
+			d.TimeSpent = timeSpent
+		}
+		if hasEntryType {
+			d.EntryType = int(entryType)
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to edit draft: %w", err)
+	}
+
+	result := fmt.Sprintf("Draft updated:\n%s", formatDraft(draft))
+	return result, nil
+}
+
+func (s *AzubiheftService) DiscardDraft(ctx context.Context, args map[string]interface{}) (string, error) {
+	draftIDs := stringListArg(args, "draft_ids")
+	if len(draftIDs) == 0 {
+		return "", fmt.Errorf("draft_ids is required")
+	}
+
+	if err := s.drafts.Delete(draftIDs...); err != nil {
+		return "", fmt.Errorf("failed to discard drafts: %w", err)
+	}
+
+	result := fmt.Sprintf("%d draft(s) discarded", len(draftIDs))
+	return result, nil
+}
+
+func (s *AzubiheftService) PublishDrafts(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	var selected []drafts.Draft
+	if draftIDs := stringListArg(args, "draft_ids"); len(draftIDs) > 0 {
+		for _, id := range draftIDs {
+			draft, err := s.drafts.Get(id)
+			if err != nil {
+				return "", err
+			}
+			selected = append(selected, draft)
+		}
+	} else {
+		from, to, err := draftRange(args)
+		if err != nil {
+			return "", err
+		}
+		if from == "" && to == "" {
+			return "", fmt.Errorf("draft_ids or a from/to date range is required")
+		}
+		selected, err = s.drafts.List(from, to)
+		if err != nil {
+			return "", fmt.Errorf("failed to list drafts: %w", err)
+		}
+	}
+
+	if len(selected) == 0 {
+		return "No drafts to publish", nil
+	}
+
+	dates := make([]time.Time, 0, len(selected))
+	for _, d := range selected {
+		if date, err := time.Parse("2006-01-02", d.Date); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	if err := policy.CheckScope(ctx, dates, len(selected)); err != nil {
+		return "", err
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	if session.IsDryRun() {
+		for _, d := range selected {
+			date, err := time.Parse("2006-01-02", d.Date)
+			if err != nil {
+				return "", fmt.Errorf("draft %s has an invalid date: %w", d.ID, err)
+			}
+			if err := session.WriteReport(date, d.Message, d.TimeSpent, d.EntryType); err != nil {
+				return "", fmt.Errorf("failed to plan draft %s: %w", d.ID, err)
+			}
+		}
+		return formatDryRun(session, fmt.Sprintf("%d draft(s) would be published and removed.", len(selected))), nil
+	}
+
+	var b strings.Builder
+	published := 0
+	for _, d := range selected {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			fmt.Fprintf(&b, "- %s (%s): failed: invalid date\n", d.ID, d.Date)
+			continue
+		}
+
+		err = s.journaled(session, journal.Record{Operation: "publish_draft"}, date, func() error {
+			return session.WriteReport(date, d.Message, d.TimeSpent, d.EntryType)
+		})
+		if err != nil {
+			fmt.Fprintf(&b, "- %s (%s): failed: %v\n", d.ID, d.Date, err)
+			continue
+		}
+
+		if err := s.drafts.Delete(d.ID); err != nil {
+			s.logger.Printf("Warning: published draft %s could not be removed: %v", d.ID, err)
+		}
+		published++
+		fmt.Fprintf(&b, "- %s (%s): published\n", d.ID, d.Date)
+	}
+
+	result := fmt.Sprintf("Published %d of %d draft(s):\n%s", published, len(selected), b.String())
+	return result, nil
+}
+
+// draftRange reads the optional from/to filter of the draft tools
+func draftRange(args map[string]interface{}) (from, to string, err error) {
+	if date, ok, err := optionalDateArg(args, "from"); err != nil {
+		return "", "", err
+	} else if ok {
+		from = date.Format("2006-01-02")
+	}
+
+	if date, ok, err := optionalDateArg(args, "to"); err != nil {
+		return "", "", err
+	} else if ok {
+		to = date.Format("2006-01-02")
+	}
+
+	return from, to, nil
+}
+
+func formatDraft(d drafts.Draft) string {
+	return fmt.Sprintf("- [%s] %s, type %d, %s: %s\n", d.ID, d.Date, d.EntryType, d.TimeSpent,
+		strings.ReplaceAll(d.Message, "\n", "\n    "))
+}
@@ -0,0 +1,42 @@
+package azubiheftserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+// mutationSession returns the session a mutating tool should use. If the
+// server runs with --dry-run or the call sets dry_run, the returned session
+// performs all lookups but only records the requests it would send.
+func (s *AzubiheftService) mutationSession(sessionID string, args map[string]interface{}) (*azubiheft.Session, error) {
+	session, err := s.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun, _ := args["dry_run"].(bool)
+	if s.dryRun || dryRun {
+		return session.DryRun(), nil
+	}
+	return session, nil
+}
+
+// formatDryRun lists the requests recorded by a dry-run session
+func formatDryRun(session *azubiheft.Session, summary string) string {
+	planned := session.PlannedRequests()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dry run, nothing was sent. %s\n", summary)
+	if len(planned) == 0 {
+		b.WriteString("No requests would be sent.")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Would send %d request(s):\n", len(planned))
+	for i, req := range planned {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, req)
+	}
+	return b.String()
+}
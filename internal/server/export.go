@@ -0,0 +1,94 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/export"
+)
+
+// ExportReport renders the entries of a date range as Markdown, CSV, JSON
+// or a printable PDF or DOCX. With a path the export is written to that file,
+// otherwise it is returned.
+func (s *AzubiheftService) ExportReport(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	from, to, err := rangeOrWeekArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	path, _ := args["path"].(string)
+	format, _ := args["format"].(string)
+	if format == "" && path != "" {
+		format, _ = export.FormatFromPath(path)
+	}
+	switch format {
+	case "":
+		format = export.FormatMarkdown
+	case export.FormatMarkdown, export.FormatCSV, export.FormatJSON, export.FormatPDF, export.FormatDOCX:
+	default:
+		return "", fmt.Errorf("invalid format %q, use %s", format, strings.Join(export.Formats, ", "))
+	}
+
+	opts := export.Options{GroupByWeek: true, Totals: true}
+	if val, ok := args["group_by_week"].(bool); ok {
+		opts.GroupByWeek = val
+	}
+	if val, ok := args["totals"].(bool); ok {
+		opts.Totals = val
+	}
+	opts.Formatted, _ = args["include_formatting"].(bool)
+	if export.Printable(format) {
+		if path == "" {
+			return "", fmt.Errorf("path is required for %s", format)
+		}
+		// Formatted texts keep their line breaks; printable formats show
+		// them as plain text
+		opts.Formatted = true
+		opts.Trainee = s.config.Trainee
+		opts.Header = s.config.ExportHeader
+	}
+
+	session, err := s.getSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	report, err := export.Fetch(session, from, to, opts.Formatted)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := export.Write(&b, format, report, opts); err != nil {
+		return "", err
+	}
+	if path == "" {
+		return b.String(), nil
+	}
+
+	if err := writeExport(path, []byte(b.String())); err != nil {
+		return "", err
+	}
+	entries := 0
+	for _, day := range report.Days {
+		entries += len(day.Entries)
+	}
+	return fmt.Sprintf("Exported %d entries on %d day(s) from %s to %s to %s",
+		entries, len(report.Days), from.Format("2006-01-02"), to.Format("2006-01-02"), path), nil
+}
+
+// writeExport writes an exported file, creating its directory if needed
+func writeExport(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package azubiheftserver
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+)
+
+// fakeSite serves the pages of Azubiheft the tools read and write: every
+// week of 2024, the subjects and the entries of each day. Mutating requests
+// are recorded in order.
+type fakeSite struct {
+	mutex    sync.Mutex
+	subjects []azubiheft.Subject // custom subjects, IDs from 8
+	entries  map[string][]fakeEntry
+	nextSeq  int
+	requests []string
+}
+
+type fakeEntry struct {
+	seq      int
+	typeName string
+	duration string
+	content  string
+}
+
+var fakeStaticSubjects = []string{"Betrieb", "Schule", "ÜBA", "Urlaub", "Feiertag", "Arbeitsunfähig", "Frei"}
+
+func newTestService(t *testing.T, cfg *config.Config) *AzubiheftService {
+	t.Helper()
+	return NewAzubiheftService(log.New(io.Discard, "", 0), "", "", Options{DataDir: t.TempDir(), Config: cfg})
+}
+
+// newSiteService returns a service whose session "test" talks to a fake
+// site
+func newSiteService(t *testing.T, cfg *config.Config) (*AzubiheftService, *fakeSite) {
+	t.Helper()
+	site := &fakeSite{entries: make(map[string][]fakeEntry), nextSeq: 1}
+	srv := httptest.NewServer(site)
+	t.Cleanup(srv.Close)
+
+	s := NewAzubiheftService(log.New(io.Discard, "", 0), "", "", Options{DataDir: t.TempDir(), Config: cfg, BaseURL: srv.URL})
+	s.sessions["test"] = s.newSession("test")
+	return s, site
+}
+
+// add stores an entry as if it had been written on the website
+func (f *fakeSite) add(date, typeName, duration, text string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	seq := f.nextSeq
+	f.nextSeq++
+	f.entries[date] = append(f.entries[date], fakeEntry{seq: seq, typeName: typeName, duration: duration, content: azubiheft.MarkdownToHTML(text)})
+	return seq
+}
+
+// day lists the entries of a date (YYYYMMDD) as "type duration text"
+func (f *fakeSite) day(date string) []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var lines []string
+	for _, e := range f.entries[date] {
+		lines = append(lines, e.typeName+" "+e.duration+" "+azubiheft.HTMLToMarkdown(e.content))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func (f *fakeSite) mutations() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+func (f *fakeSite) typeName(id string) string {
+	if n, err := strconv.Atoi(id); err == nil && n >= 1 && n <= len(fakeStaticSubjects) {
+		return fakeStaticSubjects[n-1]
+	}
+	for _, s := range f.subjects {
+		if s.ID == id {
+			return s.Name
+		}
+	}
+	return "?" + id
+}
+
+func (f *fakeSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch r.URL.Path {
+	case "/Azubi/Ausbildungsnachweise.aspx":
+		for kw := 1; kw <= 52; kw++ {
+			fmt.Fprintf(w, `<div class="mo NBox" onclick="location='Wochenansicht.aspx?NachweisNr=%d'"><div class="KW"><div>KW</div><div class="sKW">%d</div><div>2024</div></div></div>`, 5000+kw, kw)
+		}
+
+	case "/Azubi/SetupSchulfach.aspx":
+		if r.Method == http.MethodPost {
+			r.ParseForm()
+			for key, values := range r.PostForm {
+				if strings.HasPrefix(key, "txt") && len(values) > 0 {
+					f.subjects = append(f.subjects, azubiheft.Subject{ID: strconv.Itoa(8 + len(f.subjects)), Name: values[0]})
+					f.requests = append(f.requests, "add subject "+values[0])
+				}
+			}
+			return
+		}
+		fmt.Fprint(w, `<div id="divSchulfach">`)
+		for _, s := range f.subjects {
+			fmt.Fprintf(w, `<input id="ctl00_ContentPlaceHolder1_txt%s" data-default="%s" value="%s">`, s.ID, s.ID, html.EscapeString(s.Name))
+		}
+		fmt.Fprint(w, `</div>`)
+
+	case "/Azubi/Tagesbericht.aspx":
+		for _, e := range f.entries[r.URL.Query().Get("Datum")] {
+			fmt.Fprintf(w, `<div class="d0 mo" data-seq="%d"><div class="row1 d3">Art: %s</div><div class="row2 d4">%s</div><div class="row7 d5">%s</div></div>`,
+				e.seq, html.EscapeString(e.typeName), html.EscapeString(e.duration), e.content)
+		}
+
+	case "/Azubi/XMLHttpRequest.ashx":
+		r.ParseForm()
+		content, _ := url.PathUnescape(r.PostForm.Get("Inhalt"))
+		date := r.URL.Query().Get("Datum")
+		seq := r.PostForm.Get("Seq")
+		if seq == "0" {
+			f.entries[date] = append(f.entries[date], fakeEntry{
+				seq:      f.nextSeq,
+				typeName: f.typeName(r.PostForm.Get("Art_ID")),
+				duration: r.PostForm.Get("Dauer"),
+				content:  content,
+			})
+			f.nextSeq++
+			f.requests = append(f.requests, "write "+date)
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimPrefix(seq, "-"))
+		kept := f.entries[date][:0]
+		for _, e := range f.entries[date] {
+			if e.seq != n {
+				kept = append(kept, e)
+			}
+		}
+		f.entries[date] = kept
+		f.requests = append(f.requests, fmt.Sprintf("delete %s#%d", date, n))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
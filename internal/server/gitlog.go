@@ -0,0 +1,169 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/gitlog"
+)
+
+// Commits closer together than commitGap count as one stretch of work,
+// which starts commitLead before its first commit
+const (
+	commitGap  = 2 * time.Hour
+	commitLead = time.Hour
+)
+
+// DraftsFromGit creates drafts from the trainee's commits. It only writes
+// local drafts; publishing them is left to the user.
+func (s *AzubiheftService) DraftsFromGit(ctx context.Context, args map[string]interface{}) (string, error) {
+	repos := stringListArg(args, "repositories")
+	if len(repos) == 0 {
+		repos = s.config.Git.Repositories
+	}
+	if len(repos) == 0 {
+		return "", fmt.Errorf("repositories is required (or set \"git.repositories\" in the config)")
+	}
+
+	authors := stringListArg(args, "authors")
+	if len(authors) == 0 {
+		authors = s.config.Git.Authors
+	}
+	if len(authors) == 0 {
+		return "", fmt.Errorf("authors is required (or set \"git.authors\" in the config)")
+	}
+
+	from, to, err := rangeOrWeekArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	entryType := azubiheft.SubjectBetrieb
+	if val, ok := args["entry_type"].(float64); ok {
+		entryType = int(val)
+	}
+
+	since := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	until := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	byDay := make(map[string][]gitlog.Commit)
+	for _, repo := range repos {
+		commits, err := gitlog.Log(ctx, repo, authors, since, until)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range commits {
+			day := c.Time.In(time.Local).Format("2006-01-02")
+			byDay[day] = append(byDay[day], c)
+		}
+	}
+
+	if len(byDay) == 0 {
+		return fmt.Sprintf("No commits by %s between %s and %s", strings.Join(authors, ", "),
+			from.Format("2006-01-02"), to.Format("2006-01-02")), nil
+	}
+
+	maxMinutes := durationMinutes(s.config.StandardHours())
+	planned := make(map[string][]azubiheft.EntrySpec)
+	var dates []time.Time
+	var results []azubiheft.DayResult
+	for day, commits := range byDay {
+		date, _ := time.Parse("2006-01-02", day)
+		if !isWorkday(date) {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: "weekend"})
+			continue
+		}
+		if reason, skip := s.dayOff(date); skip {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+			continue
+		}
+
+		times := make([]time.Time, 0, len(commits))
+		for _, c := range commits {
+			times = append(times, c.Time)
+		}
+		minutes := int(gitlog.Estimate(times, commitGap, commitLead) / time.Minute)
+		if maxMinutes > 0 && minutes > maxMinutes {
+			minutes = maxMinutes
+		}
+
+		planned[day] = []azubiheft.EntrySpec{{
+			Message:   commitSummary(commits, len(repos) > 1),
+			TimeSpent: formatMinutes(minutes),
+			EntryType: entryType,
+		}}
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	results = append(results, s.createDrafts(dates, planned)...)
+	sortDayResults(results)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Drafts from git history:\n%s", formatDayResults(results))
+
+	created, err := s.drafts.List(from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list drafts: %w", err)
+	}
+	written := make(map[string]bool)
+	for _, r := range results {
+		if r.Status == azubiheft.DayWritten {
+			written[r.Date.Format("2006-01-02")] = true
+		}
+	}
+	if len(written) > 0 {
+		b.WriteString("\nThe drafts list the commit subjects and estimate the time from the commit times. Rewrite them as report text before publishing:\n")
+		for _, d := range created {
+			if written[d.Date] {
+				b.WriteString(formatDraft(d))
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// markdownEscaper keeps identifiers like snake_case names literal in
+// report text
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `_`, `\_`)
+
+// commitSummary lists the cleaned commit subjects of a day, optionally
+// grouped by repository
+func commitSummary(commits []gitlog.Commit, byRepo bool) string {
+	var repos []string
+	subjects := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, c := range commits {
+		if gitlog.Noise(c.Subject) {
+			continue
+		}
+		subject := gitlog.CleanSubject(c.Subject)
+		if subject == "" || seen[c.Repo+"\x00"+subject] {
+			continue
+		}
+		seen[c.Repo+"\x00"+subject] = true
+		if _, ok := subjects[c.Repo]; !ok {
+			repos = append(repos, c.Repo)
+		}
+		subjects[c.Repo] = append(subjects[c.Repo], subject)
+	}
+
+	if len(repos) == 0 {
+		return defaultPlaceholder
+	}
+
+	var lines []string
+	for _, repo := range repos {
+		if byRepo {
+			lines = append(lines, fmt.Sprintf("**%s:**", markdownEscaper.Replace(repo)))
+		}
+		for _, subject := range subjects[repo] {
+			lines = append(lines, "- "+markdownEscaper.Replace(subject))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,115 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/holidays"
+)
+
+func (s *AzubiheftService) FillHolidays(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	state := s.config.State
+	if val, ok := args["state"].(string); ok && val != "" {
+		state = strings.ToUpper(val)
+	}
+	if state == "" {
+		return "", fmt.Errorf("state is required (or set \"state\" in the config)")
+	}
+	if !holidays.ValidState(state) {
+		return "", fmt.Errorf("unknown state %q, use one of %s", state, strings.Join(holidays.States, ", "))
+	}
+
+	var from, to time.Time
+	if year, ok := args["year"].(float64); ok {
+		from = time.Date(int(year), time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = time.Date(int(year), time.December, 31, 0, 0, 0, 0, time.UTC)
+	} else {
+		var err error
+		if from, err = dateArg(args, "from"); err != nil {
+			return "", fmt.Errorf("year or from/to is required: %w", err)
+		}
+		if to, err = dateArg(args, "to"); err != nil {
+			return "", err
+		}
+	}
+
+	timeSpent := s.config.StandardHours()
+	if val, ok := args["time_spent"].(string); ok {
+		if err := validateTimeSpent(val); err != nil {
+			return "", err
+		}
+		timeSpent = val
+	}
+
+	target, err := s.targetArg(args, targetReport)
+	if err != nil {
+		return "", err
+	}
+
+	planned := make(map[string][]azubiheft.EntrySpec)
+	var dates []time.Time
+	for year := from.Year(); year <= to.Year(); year++ {
+		for _, h := range holidays.ForYear(year, state) {
+			if h.Date.Before(from) || h.Date.After(to) || !isWorkday(h.Date) {
+				continue
+			}
+			planned[h.Date.Format("2006-01-02")] = []azubiheft.EntrySpec{{
+				Message:   h.Name,
+				TimeSpent: timeSpent,
+				EntryType: azubiheft.SubjectFeiertag,
+			}}
+			dates = append(dates, h.Date)
+		}
+	}
+
+	if len(dates) == 0 {
+		return fmt.Sprintf("No public holidays on workdays in %s between %s and %s", state,
+			from.Format("2006-01-02"), to.Format("2006-01-02")), nil
+	}
+
+	if target == targetDrafts {
+		results := s.createDrafts(dates, planned)
+		return fmt.Sprintf("Holidays in %s as drafts:\n%s", state, formatDayResults(results)), nil
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := s.writeDays(ctx, session, "fill_holidays", dates, planned)
+	if err != nil {
+		return "", err
+	}
+
+	summary := fmt.Sprintf("Holidays in %s:\n%s", state, formatDayResults(results))
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
+
+// holidayWarning warns when a regular work entry is written on a public
+// holiday of the configured state
+func (s *AzubiheftService) holidayWarning(date time.Time, entryType int) string {
+	switch entryType {
+	case azubiheft.SubjectUrlaub, azubiheft.SubjectFeiertag, azubiheft.SubjectArbeitsunfahig, azubiheft.SubjectFrei:
+		return ""
+	}
+
+	holiday, ok := s.config.Holiday(date)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("\nWarning: %s is a public holiday in %s (%s). Use entry type %d (Feiertag) unless you actually worked.",
+		date.Format("2006-01-02"), s.config.State, holiday.Name, azubiheft.SubjectFeiertag)
+}
+
+func isWorkday(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
@@ -0,0 +1,305 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// journaled runs a mutation of the entries of one day. The entries are
+// snapshotted into the undo journal before the mutation starts, and the
+// resulting state is recorded once it finishes.
+func (s *AzubiheftService) journaled(session *azubiheft.Session, record journal.Record, date time.Time, mutate func() error) error {
+	if session.IsDryRun() {
+		return mutate()
+	}
+
+	before, err := session.FetchReport(date, true)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot entries before change: %w", err)
+	}
+
+	record.Date = date.Format("2006-01-02")
+	record.Before = before
+	record, err = s.journal.Begin(record)
+	if err != nil {
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+
+	mutateErr := mutate()
+
+	after, err := session.FetchReport(date, true)
+	if err != nil {
+		s.logger.Printf("Warning: journal entry %d has no after-snapshot: %v", record.ID, err)
+	}
+	record.After = after
+
+	switch {
+	case mutateErr != nil:
+		record.Status = journal.StatusFailed
+		record.Error = mutateErr.Error()
+	case err == nil && sameEntries(before, after):
+		record.Status = journal.StatusNoChange
+	default:
+		record.Status = journal.StatusDone
+	}
+
+	if err := s.journal.Complete(record); err != nil {
+		s.logger.Printf("Warning: failed to complete journal entry %d: %v", record.ID, err)
+	}
+
+	return mutateErr
+}
+
+func (s *AzubiheftService) Undo(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	records, err := s.journal.Records()
+	if err != nil {
+		return "", err
+	}
+
+	undone := make(map[int]bool)
+	for _, r := range records {
+		if r.Undoes != 0 && r.Status == journal.StatusDone {
+			undone[r.Undoes] = true
+		}
+	}
+
+	var targets []journal.Record
+	if id, ok := args["journal_id"].(float64); ok {
+		record, err := s.journal.Get(int(id))
+		if err != nil {
+			return "", err
+		}
+		if undone[record.ID] {
+			return "", fmt.Errorf("journal entry %d has already been undone", record.ID)
+		}
+		targets = append(targets, record)
+	} else {
+		count := 1
+		if val, ok := args["count"].(float64); ok {
+			count = int(val)
+		}
+		if count < 1 {
+			return "", fmt.Errorf("count must be at least 1")
+		}
+
+		for i := len(records) - 1; i >= 0 && len(targets) < count; i-- {
+			r := records[i]
+			if r.Undoes != 0 || undone[r.ID] || r.Status == journal.StatusNoChange {
+				continue
+			}
+			targets = append(targets, r)
+		}
+	}
+
+	if len(targets) == 0 {
+		return "Nothing to undo", nil
+	}
+
+	// Undo names no dates; the policy checks the days of the records and
+	// the entries they changed
+	var dates []time.Time
+	entries := 0
+	for _, record := range targets {
+		if date, err := time.Parse("2006-01-02", record.Date); err == nil {
+			dates = append(dates, date)
+		}
+		entries += changedEntries(record)
+	}
+	if err := policy.CheckScope(ctx, dates, entries); err != nil {
+		return "", err
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	subjectIDs, err := s.subjectIDs(session)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, record := range targets {
+		restored, removed, err := s.restore(session, record, subjectIDs)
+		if err != nil {
+			fmt.Fprintf(&b, "- #%d %s %s: failed: %v\n", record.ID, record.Operation, record.Date, err)
+			// Older operations may depend on this one, so stop here
+			break
+		}
+		fmt.Fprintf(&b, "- #%d %s %s: restored %d, removed %d entries\n",
+			record.ID, record.Operation, record.Date, restored, removed)
+	}
+
+	if session.IsDryRun() {
+		return formatDryRun(session, "Undo would apply:\n"+b.String()), nil
+	}
+
+	result := fmt.Sprintf("Undo results:\n%s", b.String())
+	return result, nil
+}
+
+// changedEntries counts the entries a journal record created or removed,
+// which is what undoing it changes
+func changedEntries(record journal.Record) int {
+	beforeSeqs := seqSet(record.Before)
+	afterSeqs := seqSet(record.After)
+	n := 0
+	for seq := range afterSeqs {
+		if !beforeSeqs[seq] {
+			n++
+		}
+	}
+	for seq := range beforeSeqs {
+		if !afterSeqs[seq] {
+			n++
+		}
+	}
+	return n
+}
+
+// restore brings the day of a journal record back to its state before the
+// recorded operation. Entries created by the operation are deleted and
+// entries it removed are written again.
+func (s *AzubiheftService) restore(session *azubiheft.Session, record journal.Record, subjectIDs map[string]int) (restored, removed int, err error) {
+	date, err := time.Parse("2006-01-02", record.Date)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid journal date: %w", err)
+	}
+
+	current, err := session.FetchReport(date, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	beforeSeqs := seqSet(record.Before)
+	afterSeqs := seqSet(record.After)
+	currentSeqs := seqSet(current)
+
+	var toDelete []azubiheft.ReportEntry
+	for _, e := range current {
+		if afterSeqs[e.Seq] && !beforeSeqs[e.Seq] {
+			toDelete = append(toDelete, e)
+		}
+	}
+
+	var toRestore []azubiheft.ReportEntry
+	for _, e := range record.Before {
+		if !currentSeqs[e.Seq] {
+			if _, ok := subjectIDs[e.Type]; !ok {
+				return 0, 0, fmt.Errorf("unknown entry type %q", e.Type)
+			}
+			toRestore = append(toRestore, e)
+		}
+	}
+
+	if len(toDelete) == 0 && len(toRestore) == 0 {
+		return 0, 0, nil
+	}
+
+	err = s.journaled(session, journal.Record{Operation: "undo", Undoes: record.ID}, date, func() error {
+		if err := session.DeleteEntries(date, toDelete); err != nil {
+			return err
+		}
+		for _, e := range toRestore {
+			if err := session.WriteReport(date, e.Text, e.Duration, subjectIDs[e.Type]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(toRestore), len(toDelete), nil
+}
+
+func (s *AzubiheftService) ListJournal(ctx context.Context, args map[string]interface{}) (string, error) {
+	limit := 20
+	if val, ok := args["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+
+	records, err := s.journal.Records()
+	if err != nil {
+		return "", err
+	}
+
+	if len(records) == 0 {
+		return "The undo journal is empty", nil
+	}
+
+	undone := make(map[int]bool)
+	for _, r := range records {
+		if r.Undoes != 0 && r.Status == journal.StatusDone {
+			undone[r.Undoes] = true
+		}
+	}
+
+	if len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Last %d journal entries (newest first):\n", len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		fmt.Fprintf(&b, "- #%d %s %s %s, %s, %d entries before, %d after",
+			r.ID, r.Time.Format("2006-01-02 15:04"), r.Operation, r.Date, r.Status, len(r.Before), len(r.After))
+		if r.Undoes != 0 {
+			fmt.Fprintf(&b, ", undoes #%d", r.Undoes)
+		}
+		if undone[r.ID] {
+			b.WriteString(", undone")
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// subjectIDs maps subject names to their IDs. Report entries only carry
+// the subject name, but writing an entry needs the ID.
+func (s *AzubiheftService) subjectIDs(session *azubiheft.Session) (map[string]int, error) {
+	subjects, err := session.GetSubjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %w", err)
+	}
+
+	ids := make(map[string]int, len(subjects))
+	for _, subject := range subjects {
+		var id int
+		if _, err := fmt.Sscanf(subject.ID, "%d", &id); err == nil {
+			ids[subject.Name] = id
+		}
+	}
+	return ids, nil
+}
+
+func seqSet(entries []azubiheft.ReportEntry) map[string]bool {
+	set := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		set[e.Seq] = true
+	}
+	return set
+}
+
+func sameEntries(a, b []azubiheft.ReportEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package azubiheftserver
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+)
+
+var tokenRe = regexp.MustCompile(`confirmation_token "([^"]+)"`)
+
+// confirmationToken returns the token of a confirmation preview
+func confirmationToken(t *testing.T, out string) string {
+	t.Helper()
+	m := tokenRe.FindStringSubmatch(out)
+	if m == nil {
+		t.Fatalf("no confirmation token in:\n%s", out)
+	}
+	return m[1]
+}
+
+func TestUndoWrite(t *testing.T) {
+	s, site := newSiteService(t, &config.Config{})
+	site.add("20240506", "Betrieb", "06:00", "Support")
+	ctx := context.Background()
+
+	_, err := s.WriteReport(ctx, map[string]interface{}{
+		"session_id": "test",
+		"date":       "2024-05-06",
+		"message":    "Mathe",
+		"time_spent": "02:00",
+		"entry_type": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+
+	records, err := s.journal.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Status != journal.StatusDone || len(records[0].Before) != 1 || len(records[0].After) != 2 {
+		t.Fatalf("journal = %+v, want one done record with 1 entry before and 2 after", records)
+	}
+
+	out, err := s.Undo(ctx, map[string]interface{}{"session_id": "test"})
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if !strings.Contains(out, "#1 write_report 2024-05-06: restored 0, removed 1 entries") {
+		t.Errorf("unexpected undo output:\n%s", out)
+	}
+	if got, want := site.day("20240506"), []string{"Betrieb 06:00 Support"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("entries after undo = %q, want %q", got, want)
+	}
+
+	// The undo is journaled itself and the undone record is not undone twice
+	if out, err := s.Undo(ctx, map[string]interface{}{"session_id": "test"}); err != nil || out != "Nothing to undo" {
+		t.Errorf("second Undo() = %q, %v, want nothing to undo", out, err)
+	}
+	if _, err := s.Undo(ctx, map[string]interface{}{"session_id": "test", "journal_id": float64(1)}); err == nil {
+		t.Error("undoing record 1 again succeeded")
+	}
+	list, err := s.ListJournal(ctx, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("ListJournal: %v", err)
+	}
+	if !strings.Contains(list, "undo 2024-05-06, done") || !strings.Contains(list, "undoes #1") || !strings.Contains(list, ", undone") {
+		t.Errorf("unexpected journal listing:\n%s", list)
+	}
+}
+
+func TestUndoDelete(t *testing.T) {
+	s, site := newSiteService(t, &config.Config{})
+	site.add("20240506", "Betrieb", "06:00", "Support")
+	site.add("20240506", "Schule", "02:00", "Mathe\n\n- Brüche")
+	ctx := context.Background()
+
+	args := map[string]interface{}{"session_id": "test", "date": "2024-05-06"}
+	out, err := s.DeleteReport(ctx, args)
+	if err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	args["confirmation_token"] = confirmationToken(t, out)
+	if _, err := s.DeleteReport(ctx, args); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	if got := site.day("20240506"); len(got) != 0 {
+		t.Fatalf("entries after delete = %q", got)
+	}
+
+	out, err = s.Undo(ctx, map[string]interface{}{"session_id": "test", "journal_id": float64(1)})
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if !strings.Contains(out, "restored 2, removed 0 entries") {
+		t.Errorf("unexpected undo output:\n%s", out)
+	}
+	want := []string{"Betrieb 06:00 Support", "Schule 02:00 Mathe\n\n- Brüche"}
+	if got := site.day("20240506"); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries after undo = %q, want %q", got, want)
+	}
+}
+
+func TestUndoDryRun(t *testing.T) {
+	s, site := newSiteService(t, &config.Config{})
+	ctx := context.Background()
+
+	_, err := s.WriteReport(ctx, map[string]interface{}{
+		"session_id": "test",
+		"date":       "2024-05-06",
+		"message":    "Support",
+		"time_spent": "08:00",
+		"entry_type": float64(1),
+	})
+	if err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+
+	out, err := s.Undo(ctx, map[string]interface{}{"session_id": "test", "dry_run": true})
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if !strings.Contains(out, "Dry run, nothing was sent") || !strings.Contains(out, "removed 1 entries") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if got := site.day("20240506"); len(got) != 1 {
+		t.Errorf("dry run changed the entries: %q", got)
+	}
+	if records, _ := s.journal.Records(); len(records) != 1 {
+		t.Errorf("dry run was journaled: %+v", records)
+	}
+}
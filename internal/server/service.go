@@ -4,31 +4,81 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/audit"
 	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/cache"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/drafts"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
 )
 
+// Options configures optional service behavior
+type Options struct {
+	// DataDir holds local state such as report drafts and the undo journal
+	DataDir string
+	// DryRun makes every mutating tool report its requests instead of
+	// sending them
+	DryRun bool
+	// Audit receives every upstream request and backs the audit tool
+	Audit *audit.Log
+	// ReadOnly makes every session refuse requests that change the account
+	ReadOnly bool
+	// Config holds user settings such as days off
+	Config *config.Config
+	// BaseURL overrides the address of the Azubiheft site, for example to
+	// use a test server
+	BaseURL string
+	// Cache serves reads of every session while they are fresh. Nil
+	// disables caching.
+	Cache *cache.Store
+}
+
 // AzubiheftService manages sessions and provides MCP tool implementations
 type AzubiheftService struct {
 	sessions         map[string]*azubiheft.Session
 	sessionsMutex    sync.RWMutex
 	logger           *log.Logger
 	defaultSessionID string // Auto-created session from env vars
+	drafts           *drafts.Store
+	journal          *journal.Journal
+	audit            *audit.Log
+	config           *config.Config
+	confirmations    confirmations
+	dryRun           bool
+	readOnly         bool
+	baseURL          string
+	cache            *cache.Store
 }
 
 // NewAzubiheftService creates a new service instance
-func NewAzubiheftService(logger *log.Logger, username, password string) *AzubiheftService {
+func NewAzubiheftService(logger *log.Logger, username, password string, opts Options) *AzubiheftService {
 	service := &AzubiheftService{
 		sessions: make(map[string]*azubiheft.Session),
 		logger:   logger,
+		drafts:   drafts.NewStore(filepath.Join(opts.DataDir, "drafts.json")),
+		journal:  journal.New(filepath.Join(opts.DataDir, "journal.jsonl")),
+		audit:    opts.Audit,
+		config:   opts.Config,
+		dryRun:   opts.DryRun,
+		readOnly: opts.ReadOnly,
+		baseURL:  opts.BaseURL,
+		cache:    opts.Cache,
+	}
+
+	if service.config == nil {
+		service.config = &config.Config{}
 	}
 
 	if username != "" && password != "" {
 		logger.Printf("Auto-login with provided credentials for user: %s", username)
-		session := azubiheft.NewSession()
+		session := service.newSession(username)
 		if err := session.Login(username, password); err != nil {
 			logger.Printf("Warning: Auto-login failed: %v", err)
 			logger.Println("You can still use manual login via the azubiheft_login tool")
@@ -46,6 +96,35 @@ func NewAzubiheftService(logger *log.Logger, username, password string) *Azubihe
 	return service
 }
 
+// newSession creates a client session for username wired to the audit log,
+// the cache and the read-only setting
+func (s *AzubiheftService) newSession(username string) *azubiheft.Session {
+	var opts []azubiheft.Option
+	if s.audit != nil {
+		opts = append(opts, azubiheft.WithRequestObserver(s.audit.RecordRequest))
+	}
+	if s.readOnly {
+		opts = append(opts, azubiheft.ReadOnly())
+	}
+	if s.baseURL != "" {
+		opts = append(opts, azubiheft.WithBaseURL(s.baseURL))
+	}
+	if s.cache != nil {
+		opts = append(opts, azubiheft.WithCache(s.cache.Account(s.cacheAccount(username))))
+	}
+	return azubiheft.NewSession(opts...)
+}
+
+// Close writes state that is kept in memory, such as pending cache pages
+func (s *AzubiheftService) Close() {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Flush(); err != nil {
+		s.logger.Printf("Warning: failed to save cache: %v", err)
+	}
+}
+
 func (s *AzubiheftService) GetDefaultSessionID() string {
 	return s.defaultSessionID
 }
@@ -79,7 +158,7 @@ func (s *AzubiheftService) Login(ctx context.Context, args map[string]interface{
 		return "", fmt.Errorf("password is required")
 	}
 
-	session := azubiheft.NewSession()
+	session := s.newSession(username)
 	if err := session.Login(username, password); err != nil {
 		return "", fmt.Errorf("login failed: %w", err)
 	}
@@ -165,7 +244,7 @@ func (s *AzubiheftService) AddSubject(ctx context.Context, args map[string]inter
 		return "", fmt.Errorf("subject_name is required")
 	}
 
-	session, err := s.getSession(sessionID)
+	session, err := s.mutationSession(sessionID, args)
 	if err != nil {
 		return "", err
 	}
@@ -174,6 +253,10 @@ func (s *AzubiheftService) AddSubject(ctx context.Context, args map[string]inter
 		return "", fmt.Errorf("failed to add subject: %w", err)
 	}
 
+	if session.IsDryRun() {
+		return formatDryRun(session, fmt.Sprintf("Subject '%s' would be added.", subjectName)), nil
+	}
+
 	result := fmt.Sprintf("Subject '%s' added successfully", subjectName)
 	return result, nil
 }
@@ -189,15 +272,51 @@ func (s *AzubiheftService) DeleteSubject(ctx context.Context, args map[string]in
 		return "", fmt.Errorf("subject_id is required")
 	}
 
-	session, err := s.getSession(sessionID)
+	session, err := s.mutationSession(sessionID, args)
 	if err != nil {
 		return "", err
 	}
 
+	subjects, err := session.FetchSubjects()
+	if err != nil {
+		return "", fmt.Errorf("failed to get subjects: %w", err)
+	}
+
+	var subject *azubiheft.Subject
+	for i := range subjects {
+		if subjects[i].ID == subjectID {
+			subject = &subjects[i]
+		}
+	}
+	if subject == nil {
+		return "", fmt.Errorf("subject with ID '%s' not found", subjectID)
+	}
+
+	affected := []string{subject.ID + ":" + subject.Name}
+	if !session.IsDryRun() {
+		token, _ := args["confirmation_token"].(string)
+		if token == "" {
+			token, err := s.confirmations.issue("delete_subject", subjectID, affected)
+			if err != nil {
+				return "", err
+			}
+			result := fmt.Sprintf("Subject '%s' (ID %s) will be deleted. To confirm, call azubiheft_delete_subject again with confirmation_token \"%s\" within %d minutes.",
+				subject.Name, subject.ID, token, int(confirmationTTL.Minutes()))
+			return result, nil
+		}
+		if err := s.confirmations.consume(token, "delete_subject", subjectID, affected); err != nil {
+			return "", err
+		}
+	}
+
 	if err := session.DeleteSubject(subjectID); err != nil {
 		return "", fmt.Errorf("failed to delete subject: %w", err)
 	}
 
+	if session.IsDryRun() {
+		return formatDryRun(session, fmt.Sprintf("Subject with ID '%s' would be deleted.", subjectID)), nil
+	}
+
 	result := fmt.Sprintf("Subject with ID '%s' deleted successfully", subjectID)
 	return result, nil
 }
@@ -268,16 +387,25 @@ func (s *AzubiheftService) WriteReport(ctx context.Context, args map[string]inte
 		return "", fmt.Errorf("entry_type is required")
 	}
 
-	session, err := s.getSession(sessionID)
+	session, err := s.mutationSession(sessionID, args)
 	if err != nil {
 		return "", err
 	}
 
-	if err := session.WriteReport(date, message, timeSpent, int(entryType)); err != nil {
+	err = s.journaled(session, journal.Record{Operation: "write_report"}, date, func() error {
+		return session.WriteReport(date, message, timeSpent, int(entryType))
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to write report: %w", err)
 	}
 
-	result := fmt.Sprintf("Report for %s written successfully", dateStr)
+	warning := s.holidayWarning(date, int(entryType))
+
+	if session.IsDryRun() {
+		return formatDryRun(session, fmt.Sprintf("Report for %s would be written.%s", dateStr, warning)), nil
+	}
+
+	result := fmt.Sprintf("Report for %s written successfully%s", dateStr, warning)
 	return result, nil
 }
 
@@ -303,15 +431,69 @@ func (s *AzubiheftService) DeleteReport(ctx context.Context, args map[string]int
 		entryNumber = &num
 	}
 
-	session, err := s.getSession(sessionID)
+	session, err := s.mutationSession(sessionID, args)
 	if err != nil {
 		return "", err
 	}
 
-	if err := session.DeleteReport(date, entryNumber); err != nil {
+	reports, err := session.FetchReport(date, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to get report: %w", err)
+	}
+
+	entries := reports
+	if entryNumber != nil {
+		if *entryNumber < 1 || *entryNumber > len(reports) {
+			return "", fmt.Errorf("invalid entry number: %d", *entryNumber)
+		}
+		entries = reports[*entryNumber-1 : *entryNumber]
+	}
+
+	if len(entries) == 0 {
+		return fmt.Sprintf("No reports for %s, nothing to delete", dateStr), nil
+	}
+	if err := policy.CheckScope(ctx, []time.Time{date}, len(entries)); err != nil {
+		return "", err
+	}
+
+	if !session.IsDryRun() {
+		affected := make([]string, 0, len(entries))
+		for _, e := range entries {
+			affected = append(affected, e.Seq)
+		}
+
+		token, _ := args["confirmation_token"].(string)
+		if token == "" {
+			token, err := s.confirmations.issue("delete_report", dateStr, affected)
+			if err != nil {
+				return "", err
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "%d entry(s) for %s will be deleted:\n", len(entries), dateStr)
+			for _, e := range entries {
+				fmt.Fprintf(&b, "- [Seq %s] %s, %s: %s\n", e.Seq, e.Type, e.Duration, strings.ReplaceAll(e.Text, "\n", " "))
+			}
+			fmt.Fprintf(&b, "To confirm, call azubiheft_delete_report again with the same arguments and confirmation_token \"%s\" within %d minutes.",
+				token, int(confirmationTTL.Minutes()))
+			return b.String(), nil
+		}
+		if err := s.confirmations.consume(token, "delete_report", dateStr, affected); err != nil {
+			return "", err
+		}
+	}
+
+	err = s.journaled(session, journal.Record{Operation: "delete_report"}, date, func() error {
+		return session.DeleteEntries(date, entries)
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to delete report: %w", err)
 	}
 
+	if session.IsDryRun() {
+		return formatDryRun(session, fmt.Sprintf("Report(s) for %s would be deleted.", dateStr)), nil
+	}
+
 	result := fmt.Sprintf("Report(s) for %s deleted successfully", dateStr)
 	return result, nil
 }
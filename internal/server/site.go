@@ -0,0 +1,60 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/backup"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/site"
+)
+
+// BuildSite renders every report of the account, or the weeks of a date
+// range, as a static HTML site
+func (s *AzubiheftService) BuildSite(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	dir, ok := args["dir"].(string)
+	if !ok || dir == "" {
+		return "", fmt.Errorf("dir is required")
+	}
+
+	from, _, err := optionalDateArg(args, "from")
+	if err != nil {
+		return "", err
+	}
+	to, _, err := optionalDateArg(args, "to")
+	if err != nil {
+		return "", err
+	}
+
+	session, err := s.getSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	// Only the weeks of the range are read; a missing bound is zero
+	archive, err := backup.ExportRange(session, from, to, func(done, total int) {
+		if done%10 == 0 || done == total {
+			s.logger.Printf("Read %d of %d weeks", done, total)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var subtitle []string
+	for _, part := range []string{s.config.Trainee.Name, s.config.Trainee.Occupation} {
+		if part != "" {
+			subtitle = append(subtitle, part)
+		}
+	}
+	title, _ := args["title"].(string)
+
+	summary, err := site.Build(dir, archive, site.Options{Title: title, Subtitle: strings.Join(subtitle, " · ")})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Built %d page(s) in %s: %d week(s), %d day(s) with %d entries, %d subject(s). Open %s/index.html in a browser.",
+		summary.Pages, dir, summary.Weeks, summary.Days, summary.Entries, summary.Subjects, strings.TrimRight(dir, "/")), nil
+}
@@ -0,0 +1,364 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/notes"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// Outcomes of syncing a day
+const (
+	syncInSync   = "in sync"
+	syncPushed   = "pushed"
+	syncPulled   = "pulled"
+	syncConflict = "conflict"
+	syncSkipped  = "skipped"
+	syncFailed   = "failed"
+)
+
+type syncResult struct {
+	date   time.Time
+	status string
+	reason string
+}
+
+// SyncNotes syncs a folder of daily Markdown notes with the report. Days
+// changed on one side since the last sync are copied to the other; days
+// changed on both sides are reported as conflicts.
+func (s *AzubiheftService) SyncNotes(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	dir, _ := args["dir"].(string)
+	if dir == "" {
+		dir = s.config.NotesDir
+	}
+	if dir == "" {
+		return "", fmt.Errorf("dir is required (or set \"notes_dir\" in the config)")
+	}
+
+	from, to, err := rangeOrWeekArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	direction, _ := args["direction"].(string)
+	switch direction {
+	case "":
+		direction = "both"
+	case "both", "push", "pull":
+	default:
+		return "", fmt.Errorf("invalid direction %q, use both, push or pull", direction)
+	}
+
+	prefer, _ := args["prefer"].(string)
+	switch prefer {
+	case "", "local", "remote":
+	default:
+		return "", fmt.Errorf("invalid prefer %q, use local or remote", prefer)
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := notes.LoadState(dir)
+	if err != nil {
+		return "", err
+	}
+
+	subjectIDs, err := s.subjectIDs(session)
+	if err != nil {
+		return "", err
+	}
+	subjectNames := make(map[int]string, len(subjectIDs))
+	for name, id := range subjectIDs {
+		subjectNames[id] = name
+	}
+
+	// Days are compared first and changed afterwards, so that the policy
+	// can check every day a push would write
+	var results []syncResult
+	var changes []syncChange
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := date.Format("2006-01-02")
+		result := syncResult{date: date}
+
+		local, err := notes.Read(dir, date)
+		if err == nil {
+			local, err = normalizeNoteEntries(local, subjectNames)
+		}
+		if err != nil {
+			results = append(results, syncResult{date: date, status: syncFailed, reason: err.Error()})
+			continue
+		}
+
+		remoteEntries, err := session.FetchReport(date, true)
+		if err != nil {
+			results = append(results, syncResult{date: date, status: syncFailed, reason: err.Error()})
+			continue
+		}
+		remote := make([]notes.Entry, 0, len(remoteEntries))
+		for _, e := range remoteEntries {
+			remote = append(remote, notes.Entry{Type: e.Type, Duration: e.Duration, Text: normalizeMarkdown(e.Text)})
+		}
+
+		localHash, remoteHash := notes.Hash(local), notes.Hash(remote)
+		synced, known := state.Days[day]
+		if len(local) == 0 && len(remote) == 0 {
+			// Deleted on both sides, nothing left to track
+			delete(state.Days, day)
+			continue
+		}
+
+		localChanged := localHash != synced
+		remoteChanged := remoteHash != synced
+		if !known {
+			// Without a previous sync, a missing side has not changed
+			localChanged = len(local) > 0
+			remoteChanged = len(remote) > 0
+		}
+
+		push, pull, conflict := false, false, false
+		switch {
+		case localHash == remoteHash:
+			result.status = syncInSync
+		case localChanged && remoteChanged:
+			conflict = true
+			switch prefer {
+			case "local":
+				push = true
+			case "remote":
+				pull = true
+			default:
+				result.status = syncConflict
+				result.reason = "changed in the note and on Azubiheft since the last sync"
+			}
+		case localChanged:
+			push = true
+		case remoteChanged:
+			pull = true
+		}
+
+		switch {
+		case push && len(local) == 0:
+			result.status, result.reason = syncSkipped, "note deleted, the entries on Azubiheft are kept"
+			push = false
+		case pull && len(remote) == 0:
+			result.status, result.reason = syncSkipped, "entries deleted on Azubiheft, the note is kept"
+			pull = false
+		case push && direction == "pull":
+			result.status, result.reason = syncSkipped, "note changed, not pushed"
+			push = false
+		case pull && direction == "push":
+			result.status, result.reason = syncSkipped, "Azubiheft changed, not pulled"
+			pull = false
+		}
+
+		if push || pull {
+			change := syncChange{date: date, push: push, conflict: conflict, local: local, remote: remote}
+			if push {
+				change.stale, change.missing = diffEntries(local, remote, remoteEntries)
+			}
+			changes = append(changes, change)
+			continue
+		}
+		if localHash == remoteHash {
+			state.Days[day] = localHash
+		}
+		results = append(results, result)
+	}
+
+	var pushDates []time.Time
+	var deletions []dayDeletion
+	pushEntries := 0
+	for _, c := range changes {
+		if !c.push {
+			continue
+		}
+		pushDates = append(pushDates, c.date)
+		pushEntries += len(c.stale) + len(c.missing)
+		// Entries changed on Azubiheft since the last sync would be lost
+		if c.conflict && len(c.stale) > 0 {
+			deletions = append(deletions, dayDeletion{date: c.date, entries: c.stale})
+		}
+	}
+	if err := policy.CheckScope(ctx, pushDates, pushEntries); err != nil {
+		return "", err
+	}
+
+	if !session.IsDryRun() {
+		target := from.Format("2006-01-02") + ">" + to.Format("2006-01-02")
+		preview, err := s.confirmDeletions(ctx, args, "sync_notes", target, "replaced by the notes", deletions)
+		if err != nil || preview != "" {
+			return preview, err
+		}
+	}
+
+	var weeks []azubiheft.Week
+	if len(pushDates) > 0 {
+		if weeks, err = session.GetWeeks(); err != nil {
+			return "", fmt.Errorf("failed to get weeks: %w", err)
+		}
+	}
+
+	for _, c := range changes {
+		if c.push {
+			if err := s.pushNote(session, c.date, weeks, c.stale, c.missing, subjectIDs); err != nil {
+				results = append(results, syncResult{date: c.date, status: syncFailed, reason: err.Error()})
+				continue
+			}
+			state.Days[c.date.Format("2006-01-02")] = notes.Hash(c.local)
+			results = append(results, syncResult{date: c.date, status: syncPushed})
+			continue
+		}
+
+		if !session.IsDryRun() {
+			if err := notes.Write(dir, c.date, c.remote); err != nil {
+				results = append(results, syncResult{date: c.date, status: syncFailed, reason: err.Error()})
+				continue
+			}
+		}
+		state.Days[c.date.Format("2006-01-02")] = notes.Hash(c.remote)
+		results = append(results, syncResult{date: c.date, status: syncPulled})
+	}
+
+	summary := formatSyncResults(results)
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	if err := state.Save(dir); err != nil {
+		return "", err
+	}
+	return summary, nil
+}
+
+// syncChange is a day that is copied from the note to Azubiheft (push) or
+// the other way round
+type syncChange struct {
+	date     time.Time
+	push     bool
+	conflict bool // changed on both sides since the last sync
+	local    []notes.Entry
+	remote   []notes.Entry
+	// A push deletes the stale entries of Azubiheft and writes the missing
+	// ones of the note
+	stale   []azubiheft.ReportEntry
+	missing []notes.Entry
+}
+
+// diffEntries compares a note with the entries of its day. Entries found on
+// both sides are left alone; order does not matter, as for notes.Hash.
+func diffEntries(local, remote []notes.Entry, remoteEntries []azubiheft.ReportEntry) (stale []azubiheft.ReportEntry, missing []notes.Entry) {
+	key := func(e notes.Entry) string {
+		return strings.Join([]string{e.Type, e.Duration, strings.TrimSpace(e.Text)}, "\x00")
+	}
+
+	unmatched := make(map[string][]int) // remote entries by key
+	for i, e := range remote {
+		unmatched[key(e)] = append(unmatched[key(e)], i)
+	}
+	for _, e := range local {
+		if found := unmatched[key(e)]; len(found) > 0 {
+			unmatched[key(e)] = found[1:]
+			continue
+		}
+		missing = append(missing, e)
+	}
+
+	var indexes []int
+	for _, found := range unmatched {
+		indexes = append(indexes, found...)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		stale = append(stale, remoteEntries[i])
+	}
+	return stale, missing
+}
+
+// pushNote makes the entries of a day match its note by deleting the stale
+// entries and writing the missing ones
+func (s *AzubiheftService) pushNote(session *azubiheft.Session, date time.Time, weeks []azubiheft.Week, stale []azubiheft.ReportEntry, missing []notes.Entry, subjectIDs map[string]int) error {
+	specs := make([]azubiheft.EntrySpec, 0, len(missing))
+	for _, e := range missing {
+		if err := validateTimeSpent(e.Duration); err != nil {
+			return err
+		}
+		specs = append(specs, azubiheft.EntrySpec{
+			Message:   e.Text,
+			TimeSpent: e.Duration,
+			EntryType: subjectIDs[e.Type],
+		})
+	}
+
+	return s.journaled(session, journal.Record{Operation: "sync_notes"}, date, func() error {
+		if len(stale) > 0 {
+			if err := session.DeleteEntries(date, stale); err != nil {
+				return err
+			}
+		}
+		if len(specs) == 0 {
+			return nil
+		}
+		return session.WriteEntries(date, weeks, specs)
+	})
+}
+
+// normalizeNoteEntries resolves entry types given as IDs to subject names
+// and normalizes the text the way Azubiheft stores it, so that notes and
+// report entries can be compared
+func normalizeNoteEntries(entries []notes.Entry, subjectNames map[int]string) ([]notes.Entry, error) {
+	normalized := make([]notes.Entry, 0, len(entries))
+	for _, e := range entries {
+		name := ""
+		if id, err := strconv.Atoi(e.Type); err == nil {
+			name = subjectNames[id]
+		} else {
+			for _, n := range subjectNames {
+				if strings.EqualFold(n, e.Type) {
+					name = n
+				}
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("unknown entry type %q", e.Type)
+		}
+		normalized = append(normalized, notes.Entry{Type: name, Duration: e.Duration, Text: normalizeMarkdown(e.Text)})
+	}
+	return normalized, nil
+}
+
+func normalizeMarkdown(text string) string {
+	return azubiheft.HTMLToMarkdown(azubiheft.MarkdownToHTML(strings.TrimSpace(text)))
+}
+
+func formatSyncResults(results []syncResult) string {
+	sort.SliceStable(results, func(i, j int) bool { return results[i].date.Before(results[j].date) })
+
+	counts := make(map[string]int)
+	var b strings.Builder
+	for _, r := range results {
+		counts[r.status]++
+		fmt.Fprintf(&b, "- %s %s: %s", r.date.Format("2006-01-02"), r.date.Weekday().String()[:3], r.status)
+		if r.reason != "" {
+			fmt.Fprintf(&b, " (%s)", r.reason)
+		}
+		b.WriteString("\n")
+	}
+
+	summary := fmt.Sprintf("%d pushed, %d pulled, %d in sync, %d conflict(s), %d skipped, %d failed\n",
+		counts[syncPushed], counts[syncPulled], counts[syncInSync], counts[syncConflict], counts[syncSkipped], counts[syncFailed])
+	if counts[syncConflict] > 0 {
+		summary += "Resolve conflicts by making the note match the report, or sync again with prefer set to local or remote.\n"
+	}
+	return summary + b.String()
+}
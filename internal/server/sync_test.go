@@ -0,0 +1,196 @@
+package azubiheftserver
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/notes"
+)
+
+func TestDiffEntries(t *testing.T) {
+	a := notes.Entry{Type: "Betrieb", Duration: "02:00", Text: "Support"}
+	b := notes.Entry{Type: "Schule", Duration: "02:00", Text: "Mathe"}
+	c := notes.Entry{Type: "Betrieb", Duration: "04:00", Text: "Deployment"}
+
+	tests := []struct {
+		name        string
+		local       []notes.Entry
+		remote      []notes.Entry
+		wantStale   []string // seqs of the remote entries
+		wantMissing []notes.Entry
+	}{
+		{name: "equal", local: []notes.Entry{a, b}, remote: []notes.Entry{a, b}},
+		{name: "order does not matter", local: []notes.Entry{b, a}, remote: []notes.Entry{a, b}},
+		{name: "added", local: []notes.Entry{a, b, c}, remote: []notes.Entry{a, b}, wantMissing: []notes.Entry{c}},
+		{name: "removed", local: []notes.Entry{a}, remote: []notes.Entry{a, b, c}, wantStale: []string{"2", "3"}},
+		{name: "changed", local: []notes.Entry{a, c}, remote: []notes.Entry{a, b}, wantStale: []string{"2"}, wantMissing: []notes.Entry{c}},
+		{name: "duplicate added", local: []notes.Entry{a, a, b}, remote: []notes.Entry{a, b}, wantMissing: []notes.Entry{a}},
+		{name: "duplicate removed", local: []notes.Entry{a, b}, remote: []notes.Entry{a, b, a}, wantStale: []string{"3"}},
+		{name: "duplicates kept", local: []notes.Entry{a, a}, remote: []notes.Entry{a, a}},
+		{name: "all new", local: []notes.Entry{a}, wantMissing: []notes.Entry{a}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteEntries := make([]azubiheft.ReportEntry, len(tt.remote))
+			for i, e := range tt.remote {
+				remoteEntries[i] = azubiheft.ReportEntry{Seq: strconv.Itoa(i + 1), Type: e.Type, Duration: e.Duration, Text: e.Text}
+			}
+
+			stale, missing := diffEntries(tt.local, tt.remote, remoteEntries)
+			var seqs []string
+			for _, e := range stale {
+				seqs = append(seqs, e.Seq)
+			}
+			if !reflect.DeepEqual(seqs, tt.wantStale) {
+				t.Errorf("stale = %v, want %v", seqs, tt.wantStale)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %+v, want %+v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestSyncNotes(t *testing.T) {
+	date := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	synced := notes.Entry{Type: "Betrieb", Duration: "08:00", Text: "Support"}
+	local := notes.Entry{Type: "Betrieb", Duration: "08:00", Text: "Support im Büro"}
+	remote := notes.Entry{Type: "Betrieb", Duration: "08:00", Text: "Support per Telefon"}
+
+	tests := []struct {
+		name      string
+		note      *notes.Entry // nil means no note
+		site      *notes.Entry // nil means no entries
+		known     bool         // the last sync saw synced on both sides
+		direction string
+		prefer    string
+		confirmed bool
+		want      string
+		wantNote  *notes.Entry
+		wantSite  *notes.Entry
+	}{
+		{name: "new note", note: &local, want: "pushed", wantNote: &local, wantSite: &local},
+		{name: "new entries", site: &remote, want: "pulled", wantNote: &remote, wantSite: &remote},
+		{name: "equal", note: &synced, site: &synced, want: "in sync", wantNote: &synced, wantSite: &synced},
+		{name: "note changed", note: &local, site: &synced, known: true, want: "pushed", wantNote: &local, wantSite: &local},
+		{name: "entries changed", note: &synced, site: &remote, known: true, want: "pulled", wantNote: &remote, wantSite: &remote},
+		{name: "both changed", note: &local, site: &remote, known: true, want: "conflict", wantNote: &local, wantSite: &remote},
+		{name: "both new", note: &local, site: &remote, want: "conflict", wantNote: &local, wantSite: &remote},
+		{name: "prefer remote", note: &local, site: &remote, known: true, prefer: "remote", want: "pulled", wantNote: &remote, wantSite: &remote},
+		{name: "prefer local", note: &local, site: &remote, known: true, prefer: "local", confirmed: true, want: "pushed", wantNote: &local, wantSite: &local},
+		{name: "prefer local unconfirmed", note: &local, site: &remote, known: true, prefer: "local", want: "--yes", wantNote: &local, wantSite: &remote},
+		{name: "pull only", note: &local, site: &synced, known: true, direction: "pull", want: "skipped (note changed, not pushed)", wantNote: &local, wantSite: &synced},
+		{name: "push only", note: &synced, site: &remote, known: true, direction: "push", want: "skipped (Azubiheft changed, not pulled)", wantNote: &synced, wantSite: &remote},
+		{name: "note deleted", site: &synced, known: true, want: "skipped (note deleted, the entries on Azubiheft are kept)", wantSite: &synced},
+		{name: "entries deleted", note: &synced, known: true, want: "skipped (entries deleted on Azubiheft, the note is kept)", wantNote: &synced},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, site := newSiteService(t, &config.Config{})
+			dir := t.TempDir()
+			if tt.note != nil {
+				if err := notes.Write(dir, date, []notes.Entry{*tt.note}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.site != nil {
+				site.add("20240506", tt.site.Type, tt.site.Duration, tt.site.Text)
+			}
+			if tt.known {
+				state := &notes.State{Version: 1, Days: map[string]string{"2024-05-06": notes.Hash([]notes.Entry{synced})}}
+				if err := state.Save(dir); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			args := map[string]interface{}{
+				"session_id": "test",
+				"dir":        dir,
+				"from":       "2024-05-06",
+				"to":         "2024-05-06",
+				"direction":  tt.direction,
+				"prefer":     tt.prefer,
+			}
+			ctx := CommandLine(context.Background(), tt.confirmed)
+			out, err := s.SyncNotes(ctx, args)
+			if err != nil {
+				t.Fatalf("SyncNotes: %v", err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out)
+			}
+
+			note, err := notes.Read(dir, date)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if got := entryLines(note); !reflect.DeepEqual(got, optionalEntryLines(tt.wantNote)) {
+				t.Errorf("note = %q, want %q", got, optionalEntryLines(tt.wantNote))
+			}
+			if got := site.day("20240506"); !reflect.DeepEqual(got, optionalEntryLines(tt.wantSite)) {
+				t.Errorf("Azubiheft = %q, want %q", got, optionalEntryLines(tt.wantSite))
+			}
+
+			// A copied day is in sync afterwards
+			if tt.want == "pushed" || tt.want == "pulled" {
+				delete(args, "prefer")
+				if out, err := s.SyncNotes(ctx, args); err != nil || !strings.Contains(out, "Mon: in sync") {
+					t.Errorf("second sync = %q, %v, want the day in sync", out, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncNotesDryRun(t *testing.T) {
+	s, site := newSiteService(t, &config.Config{})
+	dir := t.TempDir()
+	date := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	if err := notes.Write(dir, date, []notes.Entry{{Type: "Schule", Duration: "08:00", Text: "Mathe"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := s.SyncNotes(context.Background(), map[string]interface{}{
+		"session_id": "test",
+		"dir":        dir,
+		"week_of":    "2024-05-06",
+		"dry_run":    true,
+	})
+	if err != nil {
+		t.Fatalf("SyncNotes: %v", err)
+	}
+	if !strings.Contains(out, "Dry run, nothing was sent") || !strings.Contains(out, "Mon: pushed") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if got := site.mutations(); len(got) != 0 {
+		t.Errorf("dry run changed the account: %q", got)
+	}
+	state, err := notes.LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Days) != 0 {
+		t.Errorf("dry run saved the sync state: %v", state.Days)
+	}
+}
+
+func entryLines(entries []notes.Entry) []string {
+	var lines []string
+	for _, e := range entries {
+		lines = append(lines, e.Type+" "+e.Duration+" "+e.Text)
+	}
+	return lines
+}
+
+func optionalEntryLines(e *notes.Entry) []string {
+	if e == nil {
+		return nil
+	}
+	return entryLines([]notes.Entry{*e})
+}
@@ -0,0 +1,273 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/config"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/drafts"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/journal"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/policy"
+)
+
+// Targets of tools that generate entries
+const (
+	targetDrafts = "drafts"
+	targetReport = "report"
+)
+
+func (s *AzubiheftService) ApplyTemplate(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	name, tmpl, err := s.template(args)
+	if err != nil {
+		return "", err
+	}
+
+	days, err := tmpl.Days()
+	if err != nil {
+		return "", fmt.Errorf("template %q: %w", name, err)
+	}
+
+	from, to, err := rangeOrWeekArgs(args)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := s.targetArg(args, targetDrafts)
+	if err != nil {
+		return "", err
+	}
+
+	planned := make(map[string][]azubiheft.EntrySpec)
+	var dates []time.Time
+	var results []azubiheft.DayResult
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		entries := days[date.Weekday()]
+		if len(entries) == 0 {
+			continue
+		}
+		if reason, skip := s.dayOff(date); skip {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+			continue
+		}
+		planned[date.Format("2006-01-02")] = templateSpecs(entries)
+		dates = append(dates, date)
+	}
+
+	if target == targetDrafts {
+		results = append(results, s.createDrafts(dates, planned)...)
+		sortDayResults(results)
+		return fmt.Sprintf("Template %q applied as drafts:\n%s", name, formatDayResults(results)), nil
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	written, err := s.writeDays(ctx, session, "apply_template", dates, planned)
+	if err != nil {
+		return "", err
+	}
+	results = append(results, written...)
+	sortDayResults(results)
+
+	summary := fmt.Sprintf("Template %q applied:\n%s", name, formatDayResults(results))
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
+
+// template selects the template named in the arguments, or the only one
+func (s *AzubiheftService) template(args map[string]interface{}) (string, config.Template, error) {
+	if len(s.config.Templates) == 0 {
+		return "", nil, fmt.Errorf("no templates configured")
+	}
+
+	name, _ := args["template"].(string)
+	if name == "" {
+		if len(s.config.Templates) > 1 {
+			return "", nil, fmt.Errorf("template is required, available: %s", strings.Join(s.templateNames(), ", "))
+		}
+		for n := range s.config.Templates {
+			name = n
+		}
+	}
+
+	tmpl, ok := s.config.Templates[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown template %q, available: %s", name, strings.Join(s.templateNames(), ", "))
+	}
+	return name, tmpl, nil
+}
+
+func (s *AzubiheftService) templateNames() []string {
+	names := make([]string, 0, len(s.config.Templates))
+	for n := range s.config.Templates {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateSpecs turns template entries into entries to write. Without an
+// explicit message, the text lists the subjects with placeholders.
+func templateSpecs(entries []config.TemplateEntry) []azubiheft.EntrySpec {
+	specs := make([]azubiheft.EntrySpec, 0, len(entries))
+	for _, e := range entries {
+		message := e.Message
+		if message == "" {
+			if len(e.Subjects) == 0 {
+				message = defaultPlaceholder
+			} else {
+				lines := make([]string, 0, len(e.Subjects))
+				for _, subject := range e.Subjects {
+					lines = append(lines, fmt.Sprintf("**%s:** %s", subject, defaultPlaceholder))
+				}
+				message = strings.Join(lines, "\n")
+			}
+		}
+		specs = append(specs, azubiheft.EntrySpec{
+			Message:   message,
+			TimeSpent: e.TimeSpent,
+			EntryType: e.EntryType,
+		})
+	}
+	return specs
+}
+
+// createDrafts stores the planned entries as drafts. Days that already
+// have drafts are left alone.
+func (s *AzubiheftService) createDrafts(dates []time.Time, planned map[string][]azubiheft.EntrySpec) []azubiheft.DayResult {
+	var results []azubiheft.DayResult
+	for _, date := range dates {
+		day := date.Format("2006-01-02")
+
+		existing, err := s.drafts.List(day, day)
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+		if len(existing) > 0 {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: "day already has drafts"})
+			continue
+		}
+
+		result := azubiheft.DayResult{Date: date, Status: azubiheft.DayWritten}
+		for _, spec := range planned[day] {
+			_, err := s.drafts.Create(drafts.Draft{
+				Date:      day,
+				Message:   spec.Message,
+				TimeSpent: spec.TimeSpent,
+				EntryType: spec.EntryType,
+			})
+			if err != nil {
+				result = azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()}
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// writeDays writes the planned entries to Azubiheft. Days that already
+// have entries are skipped so that generated text never mixes with
+// entries written by hand.
+func (s *AzubiheftService) writeDays(ctx context.Context, session *azubiheft.Session, operation string, dates []time.Time, planned map[string][]azubiheft.EntrySpec) ([]azubiheft.DayResult, error) {
+	if len(dates) == 0 {
+		return nil, nil
+	}
+
+	entries := 0
+	for _, date := range dates {
+		entries += len(planned[date.Format("2006-01-02")])
+	}
+	if err := policy.CheckScope(ctx, dates, entries); err != nil {
+		return nil, err
+	}
+
+	weeks, err := session.GetWeeks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weeks: %w", err)
+	}
+
+	var results []azubiheft.DayResult
+	for _, date := range dates {
+		existing, err := session.GetReport(date, false)
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+		if len(existing) > 0 {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: "day already has entries"})
+			continue
+		}
+
+		specs := planned[date.Format("2006-01-02")]
+		err = s.journaled(session, journal.Record{Operation: operation}, date, func() error {
+			return session.WriteEntries(date, weeks, specs)
+		})
+		if err != nil {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DayFailed, Reason: err.Error()})
+			continue
+		}
+		results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DayWritten})
+	}
+	return results, nil
+}
+
+// rangeOrWeekArgs reads either a from/to range or the week containing
+// week_of
+func rangeOrWeekArgs(args map[string]interface{}) (time.Time, time.Time, error) {
+	if weekOf, ok, err := optionalDateArg(args, "week_of"); err != nil {
+		return time.Time{}, time.Time{}, err
+	} else if ok {
+		monday := mondayOf(weekOf)
+		return monday, monday.AddDate(0, 0, 6), nil
+	}
+
+	from, err := dateArg(args, "from")
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("week_of or from/to is required: %w", err)
+	}
+	to, err := dateArg(args, "to")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to must not be before from")
+	}
+	return from, to, nil
+}
+
+// targetArg reads where a tool puts the entries it creates. Writing to the
+// report is refused up front in read-only mode, instead of failing on every
+// day.
+func (s *AzubiheftService) targetArg(args map[string]interface{}, fallback string) (string, error) {
+	target, _ := args["target"].(string)
+	switch target {
+	case "":
+		return fallback, nil
+	case targetDrafts:
+		return target, nil
+	case targetReport:
+		if s.readOnly {
+			return "", fmt.Errorf("target report changes the account and is disabled in read-only mode, use target drafts")
+		}
+		return target, nil
+	}
+	return "", fmt.Errorf("invalid target %q, use drafts or report", target)
+}
+
+func sortDayResults(results []azubiheft.DayResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Date.Before(results[j].Date)
+	})
+}
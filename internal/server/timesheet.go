@@ -0,0 +1,255 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/timetrack"
+)
+
+// targetPreview only shows what an import would create
+const targetPreview = "preview"
+
+func (s *AzubiheftService) ImportTimesheet(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	preset, _ := args["preset"].(string)
+	mapping, err := s.config.TimeTracking.Mapping(preset)
+	if err != nil {
+		return "", err
+	}
+
+	target, _ := args["target"].(string)
+	if target == "" {
+		target = targetPreview
+	}
+	switch target {
+	case targetPreview:
+	case targetDrafts, targetReport:
+		if target, err = s.targetArg(args, targetDrafts); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("invalid target %q, use preview, drafts or report", target)
+	}
+
+	// from and to each bound the import on their own
+	from, hasFrom, err := optionalDateArg(args, "from")
+	if err != nil {
+		return "", err
+	}
+	to, hasTo, err := optionalDateArg(args, "to")
+	if err != nil {
+		return "", err
+	}
+	if _, hasWeek := args["week_of"]; hasWeek {
+		if from, to, err = rangeOrWeekArgs(args); err != nil {
+			return "", err
+		}
+		hasFrom, hasTo = true, true
+	}
+	if hasFrom && hasTo && to.Before(from) {
+		return "", fmt.Errorf("to must not be before from")
+	}
+
+	defaultType := 0
+	if val, ok := args["default_entry_type"].(float64); ok {
+		defaultType = int(val)
+	}
+	ignoreErrors, _ := args["ignore_errors"].(bool)
+
+	rows, rowErrors, err := timetrack.ReadFile(path, mapping)
+	if err != nil {
+		return "", err
+	}
+
+	days := make(map[string]map[int]*timesheetEntry)
+	var notes []string
+	imported := 0
+	for _, row := range rows {
+		if (hasFrom && row.Date.Before(from)) || (hasTo && row.Date.After(to)) {
+			continue
+		}
+
+		text := strings.TrimSpace(row.Project + "\n" + row.Description)
+		rule, matched := entryRule(s.config.TimeTracking.Rules, text, []string{row.Project})
+		if matched && rule.Ignore {
+			continue
+		}
+		entryType := rule.EntryType
+		if !matched {
+			entryType = defaultType
+		}
+		if entryType <= 0 {
+			notes = append(notes, fmt.Sprintf("line %d (%s): no matching rule", row.Line, strings.TrimSpace(row.Project+" "+row.Description)))
+			continue
+		}
+
+		day := row.Date.Format("2006-01-02")
+		if days[day] == nil {
+			days[day] = make(map[int]*timesheetEntry)
+		}
+		entry := days[day][entryType]
+		if entry == nil {
+			entry = &timesheetEntry{descriptions: make(map[string][]string)}
+			days[day][entryType] = entry
+		}
+		entry.add(row)
+		imported++
+	}
+
+	planned := make(map[string][]azubiheft.EntrySpec)
+	var dates []time.Time
+	var results []azubiheft.DayResult
+	for day, entries := range days {
+		date, _ := time.Parse("2006-01-02", day)
+		if reason, skip := s.dayOff(date); skip {
+			results = append(results, azubiheft.DayResult{Date: date, Status: azubiheft.DaySkipped, Reason: reason})
+			continue
+		}
+
+		types := make([]int, 0, len(entries))
+		for t := range entries {
+			types = append(types, t)
+		}
+		sort.Ints(types)
+		for _, t := range types {
+			entry := entries[t]
+			minutes := int(entry.duration.Round(time.Minute) / time.Minute)
+			if minutes <= 0 {
+				notes = append(notes, fmt.Sprintf("%s, type %d: less than a minute", day, t))
+				imported -= entry.rows
+				continue
+			}
+			planned[day] = append(planned[day], azubiheft.EntrySpec{
+				Message:   entry.message(),
+				TimeSpent: formatMinutes(minutes),
+				EntryType: t,
+			})
+		}
+		if len(planned[day]) > 0 {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var errorLines []string
+	for _, e := range rowErrors {
+		errorLines = append(errorLines, e.Error())
+	}
+
+	if target == targetPreview || (len(rowErrors) > 0 && !ignoreErrors) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "Preview: %d row(s) on %d day(s)\n", imported, len(dates))
+		for _, date := range dates {
+			day := date.Format("2006-01-02")
+			for _, spec := range planned[day] {
+				fmt.Fprintf(&b, "- %s %s, type %d, %s: %s\n", day, date.Weekday().String()[:3], spec.EntryType, spec.TimeSpent,
+					strings.ReplaceAll(spec.Message, "\n", "\n    "))
+			}
+		}
+		for _, r := range results {
+			fmt.Fprintf(&b, "- %s %s: skipped (%s)\n", r.Date.Format("2006-01-02"), r.Date.Weekday().String()[:3], r.Reason)
+		}
+		if len(errorLines) > 0 {
+			fmt.Fprintf(&b, "\nInvalid lines:\n- %s\n", strings.Join(errorLines, "\n- "))
+		}
+		b.WriteString(formatNotes(notes))
+
+		switch {
+		case target != targetPreview:
+			b.WriteString("\nNothing was imported. Fix the invalid lines or pass ignore_errors to import the valid rows.\n")
+		case len(dates) > 0:
+			b.WriteString("\nCall again with target drafts or report to import these entries.\n")
+		}
+		return b.String(), nil
+	}
+
+	if target == targetDrafts {
+		results = append(results, s.createDrafts(dates, planned)...)
+		sortDayResults(results)
+		return fmt.Sprintf("Timesheet imported as drafts:\n%s", formatDayResults(results)) + formatNotes(append(notes, errorLines...)), nil
+	}
+
+	session, err := s.mutationSession(sessionID, args)
+	if err != nil {
+		return "", err
+	}
+
+	written, err := s.writeDays(ctx, session, "import_timesheet", dates, planned)
+	if err != nil {
+		return "", err
+	}
+	results = append(results, written...)
+	sortDayResults(results)
+
+	summary := fmt.Sprintf("Timesheet imported:\n%s", formatDayResults(results)) + formatNotes(append(notes, errorLines...))
+	if session.IsDryRun() {
+		return formatDryRun(session, summary), nil
+	}
+	return summary, nil
+}
+
+// timesheetEntry collects the rows of one day and entry type
+type timesheetEntry struct {
+	duration     time.Duration
+	rows         int
+	projects     []string
+	descriptions map[string][]string
+}
+
+func (e *timesheetEntry) add(row timetrack.Row) {
+	e.duration += row.Duration
+	e.rows++
+
+	if _, ok := e.descriptions[row.Project]; !ok {
+		e.projects = append(e.projects, row.Project)
+		e.descriptions[row.Project] = nil
+	}
+	if row.Description == "" {
+		return
+	}
+	for _, d := range e.descriptions[row.Project] {
+		if d == row.Description {
+			return
+		}
+	}
+	e.descriptions[row.Project] = append(e.descriptions[row.Project], row.Description)
+}
+
+// message lists the descriptions, grouped by project if there are several
+func (e *timesheetEntry) message() string {
+	var lines []string
+	for _, project := range e.projects {
+		descriptions := e.descriptions[project]
+		if len(e.projects) > 1 && project != "" {
+			if len(descriptions) == 0 {
+				lines = append(lines, "- "+markdownEscaper.Replace(project))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("**%s:**", markdownEscaper.Replace(project)))
+		}
+		for _, d := range descriptions {
+			lines = append(lines, "- "+markdownEscaper.Replace(d))
+		}
+	}
+
+	switch {
+	case len(lines) == 0 && len(e.projects) == 1 && e.projects[0] != "":
+		return markdownEscaper.Replace(e.projects[0])
+	case len(lines) == 0:
+		return defaultPlaceholder
+	case len(lines) == 1:
+		return strings.TrimPrefix(lines[0], "- ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,148 @@
+package azubiheftserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+)
+
+func (s *AzubiheftService) VacationBalance(ctx context.Context, args map[string]interface{}) (string, error) {
+	sessionID, _ := args["session_id"].(string)
+
+	entitlement := s.config.VacationDaysPerYear
+	if entitlement <= 0 {
+		return "", fmt.Errorf("no vacation entitlement configured, set \"vacation_days_per_year\" in the config")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	date := today
+	if val, ok, err := optionalDateArg(args, "date"); err != nil {
+		return "", err
+	} else if ok {
+		date = val
+	}
+
+	includeDrafts := true
+	if val, ok := args["include_drafts"].(bool); ok {
+		includeDrafts = val
+	}
+
+	session, err := s.getSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	standard, err := time.Parse("15:04", s.config.StandardHours())
+	if err != nil {
+		return "", fmt.Errorf("invalid daily_hours: %w", err)
+	}
+	standardMinutes := standard.Hour()*60 + standard.Minute()
+
+	start, end := s.config.TrainingYear(date)
+
+	var taken, planned float64
+	var takenDays, plannedDays []string
+	written := make(map[string]bool)
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !isWorkday(day) {
+			continue
+		}
+		if _, holiday := s.config.Holiday(day); holiday {
+			continue
+		}
+
+		entries, err := session.GetReport(day, false)
+		if err != nil {
+			return "", fmt.Errorf("failed to get report for %s: %w", day.Format("2006-01-02"), err)
+		}
+
+		minutes := 0
+		for _, e := range entries {
+			if e.Type == "Urlaub" {
+				minutes += durationMinutes(e.Duration)
+			}
+		}
+		if minutes == 0 {
+			continue
+		}
+
+		days := vacationDays(minutes, standardMinutes)
+		label := fmt.Sprintf("%s (%.1f)", day.Format("2006-01-02"), days)
+		written[day.Format("2006-01-02")] = true
+		if day.After(today) {
+			planned += days
+			plannedDays = append(plannedDays, label)
+		} else {
+			taken += days
+			takenDays = append(takenDays, label)
+		}
+	}
+
+	var draftDays []string
+	if includeDrafts {
+		list, err := s.drafts.List(start.Format("2006-01-02"), end.Format("2006-01-02"))
+		if err != nil {
+			return "", fmt.Errorf("failed to list drafts: %w", err)
+		}
+
+		minutesByDay := make(map[string]int)
+		for _, d := range list {
+			if d.EntryType == azubiheft.SubjectUrlaub && !written[d.Date] {
+				minutesByDay[d.Date] += durationMinutes(d.TimeSpent)
+			}
+		}
+		for _, d := range list {
+			minutes, ok := minutesByDay[d.Date]
+			if !ok {
+				continue
+			}
+			delete(minutesByDay, d.Date)
+			days := vacationDays(minutes, standardMinutes)
+			planned += days
+			draftDays = append(draftDays, fmt.Sprintf("%s (%.1f, draft)", d.Date, days))
+		}
+	}
+
+	remaining := entitlement - taken - planned
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Training year %s to %s\n", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Entitlement: %.1f days\n", entitlement)
+	fmt.Fprintf(&b, "Taken: %.1f days\n", taken)
+	fmt.Fprintf(&b, "Planned: %.1f days\n", planned)
+	fmt.Fprintf(&b, "Remaining: %.1f days\n", remaining)
+	if remaining < 0 {
+		fmt.Fprintf(&b, "Warning: taken and planned vacation exceeds the entitlement by %.1f days\n", -remaining)
+	}
+	if len(takenDays) > 0 {
+		fmt.Fprintf(&b, "Taken days: %s\n", strings.Join(takenDays, ", "))
+	}
+	if len(plannedDays)+len(draftDays) > 0 {
+		fmt.Fprintf(&b, "Planned days: %s\n", strings.Join(append(plannedDays, draftDays...), ", "))
+	}
+	return b.String(), nil
+}
+
+// vacationDays converts vacation minutes of a day into days, counting
+// anything up to half the standard hours as a half day
+func vacationDays(minutes, standardMinutes int) float64 {
+	if standardMinutes <= 0 || minutes*2 > standardMinutes {
+		return 1
+	}
+	return 0.5
+}
+
+// durationMinutes parses an HH:MM duration, returning 0 if it is invalid
+func durationMinutes(duration string) int {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(duration, "%d:%d", &hours, &minutes); err != nil {
+		return 0
+	}
+	return hours*60 + minutes
+}
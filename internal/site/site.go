@@ -0,0 +1,423 @@
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/azubiheft"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/backup"
+	"github.com/konrad-maedler/azubiheft-mcp-server/internal/export"
+)
+
+// indexFile holds the search index and marks a folder as a generated site
+const indexFile = "search-index.js"
+
+// Options describes the site
+type Options struct {
+	Title    string
+	Subtitle string
+}
+
+// Summary counts what Build generated
+type Summary struct {
+	Weeks    int
+	Days     int
+	Entries  int
+	Subjects int
+	Pages    int
+}
+
+var weekdays = [...]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}
+
+type link struct {
+	Title string
+	URL   string
+}
+
+type entryView struct {
+	Type      string
+	TypeURL   string
+	Duration  string
+	HTML      template.HTML
+	Text      string
+	Date      string
+	DayLabel  string
+	WeekTitle string
+	URL       string
+}
+
+type dayView struct {
+	Anchor  string
+	Label   string
+	Entries []entryView
+	Hours   string
+}
+
+type weekView struct {
+	Slug    string
+	Title   string
+	Range   string
+	Days    []dayView
+	Minutes int
+	Hours   string
+	Types   []string
+	Totals  []bar
+	Prev    *link
+	Next    *link
+}
+
+type subjectView struct {
+	Name    string
+	Slug    string
+	Minutes int
+	Hours   string
+	Entries []entryView
+}
+
+type bar struct {
+	Label   string
+	URL     string
+	Hours   string
+	Percent int
+}
+
+type dashboard struct {
+	Period   string
+	Weeks    int
+	Days     int
+	Entries  int
+	Hours    string
+	Types    []bar
+	Months   []bar
+	Recent   []*weekView
+	Subjects int
+}
+
+// page is the data of a rendered page
+type page struct {
+	Root      string
+	Title     string
+	SiteTitle string
+	Subtitle  string
+	Active    string
+	Data      interface{}
+}
+
+type searchRecord struct {
+	URL  string `json:"u"`
+	Date string `json:"d"`
+	Week string `json:"w"`
+	Type string `json:"t"`
+	Text string `json:"x"`
+}
+
+// Build renders the archive as a static site in dir: a dashboard, a week
+// index with a page per week, a page per subject and a search page backed
+// by a prebuilt index. The site works without a server. dir must be empty
+// or hold a site built before, whose pages are replaced.
+func Build(dir string, archive *backup.Archive, opts Options) (Summary, error) {
+	if err := prepareDir(dir); err != nil {
+		return Summary{}, err
+	}
+	if opts.Title == "" {
+		opts.Title = "Berichtsheft"
+	}
+
+	var summary Summary
+	subjects := make(map[string]*subjectView)
+	subjectSlugs := make(map[string]bool)
+	subject := func(name string) *subjectView {
+		if s, ok := subjects[name]; ok {
+			return s
+		}
+		s := &subjectView{Name: name, Slug: uniqueSlug(name, subjectSlugs)}
+		subjects[name] = s
+		return s
+	}
+	for _, s := range archive.Subjects {
+		subject(s.Name)
+	}
+
+	weeks := append([]backup.Week(nil), archive.Weeks...)
+	sort.Slice(weeks, func(i, j int) bool {
+		if weeks[i].Year != weeks[j].Year {
+			return weeks[i].Year < weeks[j].Year
+		}
+		return weeks[i].Week.Week < weeks[j].Week.Week
+	})
+
+	var views []*weekView
+	var records []searchRecord
+	var first, last string
+	months := make(map[string]int)
+	var typeTotals []export.Total
+	typeIndex := make(map[string]int)
+	for _, w := range weeks {
+		monday := w.Monday()
+		view := &weekView{
+			Slug:  fmt.Sprintf("%d-W%02d", w.Year, w.Week.Week),
+			Title: fmt.Sprintf("KW %d/%d", w.Week.Week, w.Year),
+			Range: monday.Format("02.01.2006") + " – " + monday.AddDate(0, 0, 6).Format("02.01.2006"),
+		}
+
+		var days []export.Day
+		for _, d := range w.Days {
+			date, err := time.Parse("2006-01-02", d.Date)
+			if err != nil {
+				return summary, fmt.Errorf("archive has an invalid date %q", d.Date)
+			}
+			days = append(days, export.Day{Date: date, Entries: d.Entries})
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+
+		for _, d := range days {
+			day := dayView{
+				Anchor: d.Date.Format("2006-01-02"),
+				Label:  weekdays[d.Date.Weekday()] + ", " + d.Date.Format("02.01.2006"),
+			}
+			if first == "" {
+				first = day.Label
+			}
+			last = day.Label
+
+			minutes := 0
+			for _, e := range d.Entries {
+				s := subject(e.Type)
+				entry := entryView{
+					Type:      e.Type,
+					TypeURL:   "subjects/" + s.Slug + ".html",
+					Duration:  e.Duration,
+					HTML:      template.HTML(azubiheft.MarkdownToHTML(e.Text)),
+					Text:      azubiheft.HTMLToText(azubiheft.MarkdownToHTML(e.Text)),
+					Date:      day.Anchor,
+					DayLabel:  day.Label,
+					WeekTitle: view.Title,
+					URL:       "weeks/" + view.Slug + ".html#" + day.Anchor,
+				}
+				day.Entries = append(day.Entries, entry)
+				s.Entries = append(s.Entries, entry)
+				s.Minutes += export.Minutes(e.Duration)
+				minutes += export.Minutes(e.Duration)
+				months[d.Date.Format("2006-01")] += export.Minutes(e.Duration)
+
+				i, ok := typeIndex[e.Type]
+				if !ok {
+					i = len(typeTotals)
+					typeIndex[e.Type] = i
+					typeTotals = append(typeTotals, export.Total{Type: e.Type})
+				}
+				typeTotals[i].Minutes += export.Minutes(e.Duration)
+
+				records = append(records, searchRecord{URL: entry.URL, Date: day.Label, Week: view.Title, Type: e.Type, Text: entry.Text})
+				summary.Entries++
+			}
+			day.Hours = export.FormatMinutes(minutes)
+			view.Days = append(view.Days, day)
+			summary.Days++
+		}
+
+		totals := export.Totals(days)
+		view.Minutes = export.Sum(totals)
+		view.Hours = export.FormatMinutes(view.Minutes)
+		view.Totals = bars(totals, subjects)
+		for _, t := range totals {
+			view.Types = append(view.Types, t.Type)
+		}
+		views = append(views, view)
+	}
+	for i, view := range views {
+		if i > 0 {
+			view.Prev = &link{Title: views[i-1].Title, URL: views[i-1].Slug + ".html"}
+		}
+		if i < len(views)-1 {
+			view.Next = &link{Title: views[i+1].Title, URL: views[i+1].Slug + ".html"}
+		}
+	}
+	summary.Weeks = len(views)
+
+	var subjectList []*subjectView
+	for _, s := range subjects {
+		s.Hours = export.FormatMinutes(s.Minutes)
+		subjectList = append(subjectList, s)
+	}
+	sort.Slice(subjectList, func(i, j int) bool {
+		if subjectList[i].Minutes != subjectList[j].Minutes {
+			return subjectList[i].Minutes > subjectList[j].Minutes
+		}
+		return subjectList[i].Name < subjectList[j].Name
+	})
+	summary.Subjects = len(subjectList)
+
+	sort.SliceStable(typeTotals, func(i, j int) bool { return typeTotals[i].Minutes > typeTotals[j].Minutes })
+	dash := dashboard{
+		Weeks:    len(views),
+		Days:     summary.Days,
+		Entries:  summary.Entries,
+		Hours:    export.FormatMinutes(export.Sum(typeTotals)),
+		Types:    bars(typeTotals, subjects),
+		Subjects: len(subjectList),
+	}
+	if first != "" {
+		dash.Period = first + " – " + last
+	}
+	var monthKeys []string
+	for key := range months {
+		monthKeys = append(monthKeys, key)
+	}
+	sort.Strings(monthKeys)
+	var monthTotals []export.Total
+	for _, key := range monthKeys {
+		monthTotals = append(monthTotals, export.Total{Type: key[5:] + "/" + key[:4], Minutes: months[key]})
+	}
+	dash.Months = bars(monthTotals, nil)
+	for i := len(views) - 1; i >= 0 && len(dash.Recent) < 5; i-- {
+		if len(views[i].Days) > 0 {
+			dash.Recent = append(dash.Recent, views[i])
+		}
+	}
+
+	render := func(path, tmpl string, p page) error {
+		p.SiteTitle, p.Subtitle = opts.Title, opts.Subtitle
+		var b bytes.Buffer
+		if err := templates.ExecuteTemplate(&b, tmpl, p); err != nil {
+			return fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		summary.Pages++
+		return writeFile(filepath.Join(dir, path), b.Bytes())
+	}
+
+	if err := render("index.html", "dashboard", page{Title: "Übersicht", Active: "dashboard", Data: dash}); err != nil {
+		return summary, err
+	}
+	if err := render("weeks.html", "weeks", page{Title: "Wochen", Active: "weeks", Data: views}); err != nil {
+		return summary, err
+	}
+	for _, view := range views {
+		if err := render(filepath.Join("weeks", view.Slug+".html"), "week", page{Root: "../", Title: view.Title, Active: "weeks", Data: view}); err != nil {
+			return summary, err
+		}
+	}
+	if err := render("subjects.html", "subjects", page{Title: "Arten", Active: "subjects", Data: subjectList}); err != nil {
+		return summary, err
+	}
+	for _, s := range subjectList {
+		if err := render(filepath.Join("subjects", s.Slug+".html"), "subject", page{Root: "../", Title: s.Name, Active: "subjects", Data: s}); err != nil {
+			return summary, err
+		}
+	}
+	if err := render("search.html", "search", page{Title: "Suche", Active: "search"}); err != nil {
+		return summary, err
+	}
+
+	index, err := json.Marshal(records)
+	if err != nil {
+		return summary, fmt.Errorf("failed to encode search index: %w", err)
+	}
+	// A script instead of a plain JSON file, so that the search also works
+	// for pages opened from disk, where browsers block fetching files
+	script := append([]byte("window.SEARCH_INDEX = "), index...)
+	script = append(script, ";\n"...)
+	for name, content := range map[string][]byte{
+		indexFile:   script,
+		"search.js": []byte(searchScript),
+		"style.css": []byte(styleSheet),
+	} {
+		if err := writeFile(filepath.Join(dir, name), content); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// bars scales totals relative to the largest one. Subjects, if given, link
+// each bar to its subject page.
+func bars(totals []export.Total, subjects map[string]*subjectView) []bar {
+	max := 0
+	for _, t := range totals {
+		if t.Minutes > max {
+			max = t.Minutes
+		}
+	}
+
+	out := make([]bar, 0, len(totals))
+	for _, t := range totals {
+		b := bar{Label: t.Type, Hours: export.FormatMinutes(t.Minutes)}
+		if max > 0 {
+			b.Percent = t.Minutes * 100 / max
+		}
+		if s, ok := subjects[t.Type]; ok {
+			b.URL = "subjects/" + s.Slug + ".html"
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// prepareDir makes sure dir can take the site and removes the pages of a
+// previous build
+func prepareDir(dir string) error {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if len(files) > 0 {
+		if _, err := os.Stat(filepath.Join(dir, indexFile)); err != nil {
+			return fmt.Errorf("%s is not empty and holds no site built before", dir)
+		}
+	}
+	for _, sub := range []string{"weeks", "subjects"} {
+		if err := os.RemoveAll(filepath.Join(dir, sub)); err != nil {
+			return fmt.Errorf("failed to remove old pages: %w", err)
+		}
+	}
+	return nil
+}
+
+var slugReplacer = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+
+// uniqueSlug turns name into a file name that is not in used yet
+func uniqueSlug(name string, used map[string]bool) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range slugReplacer.Replace(strings.ToLower(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		slug = "art"
+	}
+
+	candidate := slug
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package site
+
+import "html/template"
+
+// URLs in the page data are relative to the site root; templates prefix
+// them with .Root, which leads from the page back to the root
+var templates = template.Must(template.New("site").Funcs(template.FuncMap{"dict": dict}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} – {{.SiteTitle}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header>
+<div class="brand"><a href="{{.Root}}index.html">{{.SiteTitle}}</a>{{if .Subtitle}}<span>{{.Subtitle}}</span>{{end}}</div>
+<nav>
+<a href="{{.Root}}index.html"{{if eq .Active "dashboard"}} class="active"{{end}}>Übersicht</a>
+<a href="{{.Root}}weeks.html"{{if eq .Active "weeks"}} class="active"{{end}}>Wochen</a>
+<a href="{{.Root}}subjects.html"{{if eq .Active "subjects"}} class="active"{{end}}>Arten</a>
+<form action="{{.Root}}search.html" method="get"><input type="search" name="q" placeholder="Suchen…" aria-label="Suchen"></form>
+</nav>
+</header>
+<main>
+{{end}}
+
+{{define "foot"}}</main>
+</body>
+</html>
+{{end}}
+
+{{define "bars"}}<table class="bars">
+{{range .Bars}}<tr><th>{{if .URL}}<a href="{{$.Root}}{{.URL}}">{{.Label}}</a>{{else}}{{.Label}}{{end}}</th><td class="bar"><span style="width: {{.Percent}}%"></span></td><td class="num">{{.Hours}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{define "entries"}}{{range .Entries}}<div class="entry">
+<div class="meta"><a class="type" href="{{$.Root}}{{.TypeURL}}">{{.Type}}</a><span class="num">{{.Duration}}</span></div>
+<div class="text">{{.HTML}}</div>
+</div>
+{{end}}{{end}}
+
+{{define "dashboard"}}{{template "head" .}}{{with .Data}}
+<h1>Übersicht</h1>
+{{if .Period}}<p class="muted">{{.Period}}</p>{{end}}
+<div class="cards">
+<div class="card"><strong>{{.Weeks}}</strong>Wochen</div>
+<div class="card"><strong>{{.Days}}</strong>Tage</div>
+<div class="card"><strong>{{.Entries}}</strong>Einträge</div>
+<div class="card"><strong>{{.Hours}}</strong>Stunden</div>
+</div>
+<h2>Stunden nach Art</h2>
+{{template "bars" (dict "Root" $.Root "Bars" .Types)}}
+<h2>Stunden pro Monat</h2>
+{{template "bars" (dict "Root" $.Root "Bars" .Months)}}
+{{if .Recent}}<h2>Letzte Wochen</h2>
+<ul class="plain">
+{{range .Recent}}<li><a href="{{$.Root}}weeks/{{.Slug}}.html">{{.Title}}</a> <span class="muted">{{.Range}} · {{.Hours}} Stunden</span></li>
+{{end}}</ul>{{end}}
+{{end}}{{template "foot" .}}{{end}}
+
+{{define "weeks"}}{{template "head" .}}
+<h1>Wochen</h1>
+<table class="list">
+<thead><tr><th>Woche</th><th>Zeitraum</th><th>Tage</th><th>Arten</th><th class="num">Stunden</th></tr></thead>
+<tbody>
+{{range .Data}}<tr><td><a href="{{$.Root}}weeks/{{.Slug}}.html">{{.Title}}</a></td><td>{{.Range}}</td><td>{{len .Days}}</td><td>{{range $i, $t := .Types}}{{if $i}}, {{end}}{{$t}}{{end}}</td><td class="num">{{.Hours}}</td></tr>
+{{end}}</tbody>
+</table>
+{{template "foot" .}}{{end}}
+
+{{define "week"}}{{template "head" .}}{{with .Data}}
+<nav class="pager">{{if .Prev}}<a href="{{.Prev.URL}}">← {{.Prev.Title}}</a>{{else}}<span></span>{{end}}{{if .Next}}<a href="{{.Next.URL}}">{{.Next.Title}} →</a>{{end}}</nav>
+<h1>{{.Title}}</h1>
+<p class="muted">{{.Range}}</p>
+{{range .Days}}<section id="{{.Anchor}}">
+<h2>{{.Label}} <span class="muted">{{.Hours}} Stunden</span></h2>
+{{template "entries" (dict "Root" $.Root "Entries" .Entries)}}
+</section>
+{{else}}<p class="muted">Keine Einträge.</p>
+{{end}}
+{{if .Totals}}<h2>Stunden nach Art</h2>
+{{template "bars" (dict "Root" $.Root "Bars" .Totals)}}
+<p><strong>Gesamt: {{.Hours}} Stunden</strong></p>{{end}}
+{{end}}{{template "foot" .}}{{end}}
+
+{{define "subjects"}}{{template "head" .}}
+<h1>Arten</h1>
+<table class="list">
+<thead><tr><th>Art</th><th>Einträge</th><th class="num">Stunden</th></tr></thead>
+<tbody>
+{{range .Data}}<tr><td><a href="{{$.Root}}subjects/{{.Slug}}.html">{{.Name}}</a></td><td>{{len .Entries}}</td><td class="num">{{.Hours}}</td></tr>
+{{end}}</tbody>
+</table>
+{{template "foot" .}}{{end}}
+
+{{define "subject"}}{{template "head" .}}{{with .Data}}
+<h1>{{.Name}}</h1>
+<p class="muted">{{len .Entries}} Einträge · {{.Hours}} Stunden</p>
+{{range .Entries}}<div class="entry">
+<div class="meta"><a href="{{$.Root}}{{.URL}}">{{.DayLabel}}</a> <span class="muted">{{.WeekTitle}}</span><span class="num">{{.Duration}}</span></div>
+<div class="text">{{.HTML}}</div>
+</div>
+{{else}}<p class="muted">Keine Einträge.</p>
+{{end}}
+{{end}}{{template "foot" .}}{{end}}
+
+{{define "search"}}{{template "head" .}}
+<h1>Suche</h1>
+<form class="search" action="search.html" method="get"><input type="search" id="q" name="q" placeholder="Begriffe, Art oder Datum" autofocus></form>
+<p id="status" class="muted"></p>
+<div id="results"></div>
+<noscript><p>Die Suche benötigt JavaScript.</p></noscript>
+<script src="search-index.js"></script>
+<script src="search.js"></script>
+{{template "foot" .}}{{end}}
+`))
+
+// dict passes several values to a nested template
+func dict(pairs ...interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key, _ := pairs[i].(string)
+		m[key] = pairs[i+1]
+	}
+	return m
+}
+
+const searchScript = `(function () {
+  var input = document.getElementById("q");
+  var status = document.getElementById("status");
+  var results = document.getElementById("results");
+  var index = window.SEARCH_INDEX || [];
+
+  function escape(text) {
+    return text.replace(/[&<>"']/g, function (c) {
+      return {"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;"}[c];
+    });
+  }
+
+  function snippet(text, term) {
+    var i = text.toLowerCase().indexOf(term);
+    var start = Math.max(0, i - 80);
+    var part = text.slice(start, start + 240);
+    return (start > 0 ? "…" : "") + escape(part) + (start + 240 < text.length ? "…" : "");
+  }
+
+  function search() {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.innerHTML = "";
+    if (terms.length === 0) {
+      status.textContent = index.length + " Einträge durchsuchbar";
+      return;
+    }
+    var hits = index.filter(function (r) {
+      var haystack = (r.x + " " + r.t + " " + r.d + " " + r.w).toLowerCase();
+      return terms.every(function (t) { return haystack.indexOf(t) >= 0; });
+    });
+    status.textContent = hits.length + " Treffer";
+    var html = "";
+    hits.slice(0, 200).forEach(function (r) {
+      html += '<div class="entry"><div class="meta"><a href="' + escape(r.u) + '">' + escape(r.d) + "</a> " +
+        '<span class="muted">' + escape(r.w) + " · " + escape(r.t) + "</span></div>" +
+        '<div class="text">' + snippet(r.x, terms[0]) + "</div></div>";
+    });
+    results.innerHTML = html;
+  }
+
+  input.value = new URLSearchParams(window.location.search).get("q") || "";
+  input.addEventListener("input", search);
+  search();
+})();
+`
+
+const styleSheet = `* { box-sizing: border-box; }
+body { margin: 0; font: 15px/1.5 system-ui, -apple-system, "Segoe UI", Roboto, sans-serif; color: #222; background: #f6f7f9; }
+a { color: #0b5cad; text-decoration: none; }
+a:hover { text-decoration: underline; }
+header { display: flex; flex-wrap: wrap; align-items: center; justify-content: space-between; gap: 12px; padding: 12px 24px; background: #fff; border-bottom: 1px solid #ddd; }
+.brand a { font-weight: 700; font-size: 18px; color: #222; }
+.brand span { margin-left: 12px; color: #666; }
+nav { display: flex; align-items: center; gap: 16px; }
+nav a.active { font-weight: 700; }
+nav input { padding: 4px 8px; border: 1px solid #ccc; border-radius: 4px; }
+main { max-width: 960px; margin: 0 auto; padding: 24px; }
+h1 { margin: 0 0 4px; }
+h2 { margin: 28px 0 8px; font-size: 18px; }
+.muted { color: #666; font-weight: normal; }
+.num { text-align: right; white-space: nowrap; font-variant-numeric: tabular-nums; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin: 16px 0; }
+.card { flex: 1 1 140px; padding: 12px 16px; background: #fff; border: 1px solid #ddd; border-radius: 6px; color: #666; }
+.card strong { display: block; font-size: 24px; color: #222; }
+table { width: 100%; border-collapse: collapse; background: #fff; }
+table.list th, table.list td { padding: 6px 10px; border-bottom: 1px solid #e5e5e5; text-align: left; }
+table.list th.num, table.list td.num { text-align: right; }
+table.bars th { width: 160px; padding: 4px 10px 4px 0; text-align: left; font-weight: normal; }
+table.bars td { padding: 4px 0; }
+table.bars td.bar { width: 100%; }
+table.bars td.bar span { display: block; height: 14px; background: #0b5cad; border-radius: 2px; min-width: 2px; }
+table.bars td.num { padding-left: 10px; }
+.entry { margin: 8px 0; padding: 10px 14px; background: #fff; border: 1px solid #ddd; border-radius: 6px; }
+.entry .meta { display: flex; gap: 8px; align-items: baseline; }
+.entry .meta .num { margin-left: auto; color: #666; }
+.entry .type { font-weight: 600; }
+.entry .text div:empty, .entry .text ul, .entry .text ol { margin: 4px 0; }
+.pager { display: flex; justify-content: space-between; margin-bottom: 12px; }
+ul.plain { padding-left: 18px; }
+form.search input { width: 100%; padding: 8px 12px; font-size: 16px; border: 1px solid #ccc; border-radius: 4px; }
+@media print { header { display: none; } body { background: #fff; } .entry { break-inside: avoid; } }
+`
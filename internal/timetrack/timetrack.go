@@ -0,0 +1,337 @@
+package timetrack
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mapping names the CSV columns of an export. Duration may be left empty
+// if the rows have start and end times instead.
+type Mapping struct {
+	Date        string `json:"date,omitempty"`
+	Duration    string `json:"duration,omitempty"`
+	Start       string `json:"start,omitempty"`
+	End         string `json:"end,omitempty"`
+	Project     string `json:"project,omitempty"`
+	Description string `json:"description,omitempty"`
+	// DateFormat is a Go layout such as "02.01.2006"; common formats are
+	// detected if it is empty
+	DateFormat string `json:"date_format,omitempty"`
+	// Delimiter is detected from the header line if it is empty
+	Delimiter string `json:"delimiter,omitempty"`
+}
+
+// Presets are the column mappings of common exports
+var Presets = map[string]Mapping{
+	// Toggl Track, detailed report
+	"toggl": {
+		Date:        "Start date",
+		Duration:    "Duration",
+		Start:       "Start time",
+		End:         "End time",
+		Project:     "Project",
+		Description: "Description",
+	},
+	// Clockify, detailed report
+	"clockify": {
+		Date:        "Start Date",
+		Duration:    "Duration (h)",
+		Start:       "Start Time",
+		End:         "End Time",
+		Project:     "Project",
+		Description: "Description",
+	},
+	// German Excel sheets saved as CSV
+	"excel": {
+		Date:        "Datum",
+		Duration:    "Dauer",
+		Start:       "Beginn",
+		End:         "Ende",
+		Project:     "Projekt",
+		Description: "Beschreibung",
+		Delimiter:   ";",
+	},
+}
+
+// Row is a valid line of an export
+type Row struct {
+	Line        int
+	Date        time.Time
+	Duration    time.Duration
+	Project     string
+	Description string
+}
+
+// RowError describes a line that could not be read
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ReadFile reads the export at path
+func ReadFile(path string, m Mapping) ([]Row, []RowError, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	return Read(f, m)
+}
+
+// Read parses an export. Lines with invalid values are returned as
+// RowErrors so that they can be shown before anything is imported; the
+// error is only set if the file as a whole is unusable.
+func Read(r io.Reader, m Mapping) ([]Row, []RowError, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	// A byte order mark before a quoted first column would become part of
+	// the column name
+	sample := string(header)
+	if strings.HasPrefix(sample, "\ufeff") {
+		br.Discard(len("\ufeff"))
+		sample = sample[len("\ufeff"):]
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = delimiter(m.Delimiter, sample)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("CSV is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(name)
+		columns[strings.ToLower(name)] = i
+	}
+	col := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		if i, ok := columns[strings.ToLower(name)]; ok {
+			return i
+		}
+		return -1
+	}
+
+	dateCol, durationCol := col(m.Date), col(m.Duration)
+	startCol, endCol := col(m.Start), col(m.End)
+	projectCol, descriptionCol := col(m.Project), col(m.Description)
+	if dateCol < 0 {
+		return nil, nil, fmt.Errorf("date column %q not found, columns are: %s", m.Date, strings.Join(records[0], ", "))
+	}
+	if durationCol < 0 && (startCol < 0 || endCol < 0) {
+		return nil, nil, fmt.Errorf("duration column %q or start/end columns not found, columns are: %s", m.Duration, strings.Join(records[0], ", "))
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+	for i, record := range records[1:] {
+		line := i + 2
+		field := func(c int) string {
+			if c < 0 || c >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[c])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		date, err := parseDate(field(dateCol), m.DateFormat)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: err})
+			continue
+		}
+
+		var duration time.Duration
+		if value := field(durationCol); value != "" {
+			duration, err = ParseDuration(value)
+		} else {
+			duration, err = span(field(startCol), field(endCol))
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: err})
+			continue
+		}
+		if duration <= 0 {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: fmt.Errorf("duration is zero")})
+			continue
+		}
+		if duration > 24*time.Hour {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: fmt.Errorf("duration %s is longer than a day", duration)})
+			continue
+		}
+
+		rows = append(rows, Row{
+			Line:        line,
+			Date:        date,
+			Duration:    duration,
+			Project:     field(projectCol),
+			Description: field(descriptionCol),
+		})
+	}
+	return rows, rowErrors, nil
+}
+
+// delimiter returns the configured delimiter or the most frequent
+// candidate in the header line
+func delimiter(configured, sample string) rune {
+	if configured == `\t` || configured == "tab" {
+		return '\t'
+	}
+	if configured != "" {
+		return []rune(configured)[0]
+	}
+	header, _, _ := strings.Cut(sample, "\n")
+	best, count := ',', 0
+	for _, c := range []rune{',', ';', '\t'} {
+		if n := strings.Count(header, string(c)); n > count {
+			best, count = c, n
+		}
+	}
+	return best
+}
+
+var dateFormats = []string{
+	"2006-01-02",
+	"02.01.2006",
+	"2.1.2006",
+	"02.01.06",
+	"01/02/2006",
+	"1/2/2006",
+}
+
+func parseDate(value, layout string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date is empty")
+	}
+	layouts := []string{layout}
+	if layout == "" {
+		layouts = dateFormats
+		// Drop a time of day, e.g. "2025-03-04 09:00:00" or "2025-03-04T09:00"
+		if i := strings.IndexAny(value, " T"); i > 0 {
+			value = value[:i]
+		}
+	}
+	for _, l := range layouts {
+		if t, err := time.Parse(l, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+var unitRe = regexp.MustCompile(`^(?:(\d+(?:[.,]\d+)?)\s*h)?\s*(?:(\d+)\s*m(?:in)?)?$`)
+
+// ParseDuration accepts H:MM, H:MM:SS, decimal hours ("1.5" or "1,5")
+// and units ("1h 30m")
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		var d time.Duration
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			d += time.Duration(n) * units[i]
+		}
+		return d, nil
+	}
+
+	if hours, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64); err == nil {
+		if hours < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(hours * float64(time.Hour)).Round(time.Second), nil
+	}
+
+	if m := unitRe.FindStringSubmatch(value); m != nil && value != "" {
+		var d time.Duration
+		if m[1] != "" {
+			hours, _ := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+			d += time.Duration(hours * float64(time.Hour))
+		}
+		if m[2] != "" {
+			minutes, _ := strconv.Atoi(m[2])
+			d += time.Duration(minutes) * time.Minute
+		}
+		return d.Round(time.Second), nil
+	}
+	return 0, fmt.Errorf("invalid duration %q", value)
+}
+
+// span computes the time between a start and end time of the same day;
+// an end before the start is taken to be after midnight
+func span(start, end string) (time.Duration, error) {
+	if start == "" || end == "" {
+		return 0, fmt.Errorf("duration or start and end time is required")
+	}
+	from, err := parseClock(start)
+	if err != nil {
+		return 0, err
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return 0, err
+	}
+	if to < from {
+		to += 24 * time.Hour
+	}
+	return to - from, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	candidates := []string{strings.ToUpper(value)}
+	// Some exports include the date, e.g. "2025-03-04 09:00:00"
+	if date, clock, ok := strings.Cut(value, " "); ok && strings.ContainsAny(date, "-./") {
+		candidates = append(candidates, strings.ToUpper(clock))
+	}
+	for _, c := range candidates {
+		for _, layout := range []string{"15:04:05", "15:04", "3:04:05 PM", "3:04 PM"} {
+			if t, err := time.Parse(layout, c); err == nil {
+				return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q", value)
+}
+
+// PresetNames lists the available presets
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,230 @@
+package timetrack
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration // 0 means an error
+	}{
+		{"1:30", 90 * time.Minute},
+		{"01:30:00", 90 * time.Minute},
+		{"0:00:45", 45 * time.Second},
+		{"10:05", 10*time.Hour + 5*time.Minute},
+		{"1.5", 90 * time.Minute},
+		{"1,25", 75 * time.Minute},
+		{"8", 8 * time.Hour},
+		{"0.333333", 20 * time.Minute},
+		{"1h 30m", 90 * time.Minute},
+		{"1h30min", 90 * time.Minute},
+		{"2H", 2 * time.Hour},
+		{"1,5h", 90 * time.Minute},
+		{"45m", 45 * time.Minute},
+		{" 2:00 ", 2 * time.Hour},
+		{"", 0},
+		{"-1", 0},
+		{"1:-5", 0},
+		{"1:2:3:4", 0},
+		{"1:xx", 0},
+		{"zwei Stunden", 0},
+		{"1d", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if tt.want == 0 {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %s, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDelimiter(t *testing.T) {
+	tests := []struct {
+		configured string
+		sample     string
+		want       rune
+	}{
+		{"", "Datum,Dauer,Projekt\n1;2;3;4;5", ','},
+		{"", "Datum;Dauer;Projekt\n1,2,3,4,5", ';'},
+		{"", "Datum\tDauer\tProjekt", '\t'},
+		{"", `"Project, Client";Description;Duration`, ';'},
+		{"", "Datum", ','},
+		{"", "", ','},
+		{";", "Datum,Dauer,Projekt", ';'},
+		{"tab", "Datum,Dauer", '\t'},
+		{`\t`, "Datum,Dauer", '\t'},
+		{"|", "Datum|Dauer", '|'},
+	}
+	for _, tt := range tests {
+		if got := delimiter(tt.configured, tt.sample); got != tt.want {
+			t.Errorf("delimiter(%q, %q) = %q, want %q", tt.configured, tt.sample, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value  string
+		layout string
+		want   string // "" means an error
+	}{
+		{"2024-05-06", "", "2024-05-06"},
+		{"06.05.2024", "", "2024-05-06"},
+		{"6.5.2024", "", "2024-05-06"},
+		{"06.05.24", "", "2024-05-06"},
+		{"05/06/2024", "", "2024-05-06"},
+		{"5/6/2024", "", "2024-05-06"},
+		{"2024-05-06 09:00:00", "", "2024-05-06"},
+		{"2024-05-06T09:00", "", "2024-05-06"},
+		{"06/05/2024", "02/01/2006", "2024-05-06"},
+		{"2024-05-06", "02.01.2006", ""},
+		{"2024-13-01", "", ""},
+		{"gestern", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.value, tt.layout)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("parseDate(%q, %q) = %s, want an error", tt.value, tt.layout, got.Format("2006-01-02"))
+			}
+			continue
+		}
+		if err != nil || got.Format("2006-01-02") != tt.want {
+			t.Errorf("parseDate(%q, %q) = %s, %v, want %s", tt.value, tt.layout, got.Format("2006-01-02"), err, tt.want)
+		}
+	}
+}
+
+func TestSpan(t *testing.T) {
+	tests := []struct {
+		start, end string
+		want       time.Duration // 0 means an error
+	}{
+		{"08:00", "11:30", 210 * time.Minute},
+		{"08:00:00", "08:00:30", 30 * time.Second},
+		{"9:15 AM", "1:45 PM", 270 * time.Minute},
+		{"2024-05-06 23:30:00", "2024-05-07 00:30:00", time.Hour},
+		{"22:00", "02:00", 4 * time.Hour},
+		{"08:00", "", 0},
+		{"acht", "11:00", 0},
+	}
+	for _, tt := range tests {
+		got, err := span(tt.start, tt.end)
+		if tt.want == 0 {
+			if err == nil {
+				t.Errorf("span(%q, %q) = %s, want an error", tt.start, tt.end, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("span(%q, %q) = %s, %v, want %s", tt.start, tt.end, got, err, tt.want)
+		}
+	}
+}
+
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		preset    string
+		file      string
+		wantRows  []string
+		wantLines []int // lines with errors
+	}{
+		{
+			preset: "clockify",
+			file:   "testdata/clockify.csv",
+			wantRows: []string{
+				"2 2024-05-06 3h30m0s Support: Tickets bearbeitet",
+				"3 2024-05-07 5h0m0s Berufsschule: Mathe",
+				"4 2024-05-07 1h0m0s Support: Deployment",
+			},
+		},
+		{
+			preset: "toggl",
+			file:   "testdata/toggl.csv",
+			wantRows: []string{
+				"2 2024-05-06 2h15m0s Support: Tickets, Telefon",
+				"3 2024-05-06 1h30m0s Berufsschule: Englisch",
+			},
+			wantLines: []int{4, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			rows, rowErrors, err := ReadFile(tt.file, Presets[tt.preset])
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if got := rowLines(rows); !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("rows = %q, want %q", got, tt.wantRows)
+			}
+			var lines []int
+			for _, e := range rowErrors {
+				lines = append(lines, e.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("errors on lines %v, want %v: %v", lines, tt.wantLines, rowErrors)
+			}
+		})
+	}
+}
+
+func TestReadExcel(t *testing.T) {
+	csv := "Datum;Beginn;Ende;Dauer;Projekt;Beschreibung\n" +
+		"06.05.2024;;;1,5;Schule;Mathe\n" +
+		";;;;;\n" +
+		"07.05.2024;08:00;12:00;;Betrieb;Support\n" +
+		"08.05.2024;08:00;;;Betrieb;Ohne Ende\n" +
+		"09.05.2024;;;25:00;Betrieb;Zu lang\n"
+
+	rows, rowErrors, err := Read(strings.NewReader(csv), Presets["excel"])
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := []string{
+		"2 2024-05-06 1h30m0s Schule: Mathe",
+		"4 2024-05-07 4h0m0s Betrieb: Support",
+	}
+	if got := rowLines(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+	if len(rowErrors) != 2 || rowErrors[0].Line != 5 || rowErrors[1].Line != 6 {
+		t.Errorf("errors = %v, want lines 5 and 6", rowErrors)
+	}
+}
+
+func TestReadMissingColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"empty", ""},
+		{"no date", "Dauer;Projekt\n1:00;Schule\n"},
+		{"no duration", "Datum;Beginn;Projekt\n06.05.2024;08:00;Schule\n"},
+	}
+	for _, tt := range tests {
+		if _, _, err := Read(strings.NewReader(tt.csv), Presets["excel"]); err == nil {
+			t.Errorf("%s: Read() succeeded, want an error", tt.name)
+		}
+	}
+}
+
+// rowLines formats rows as "line date duration project: description"
+func rowLines(rows []Row) []string {
+	var lines []string
+	for _, r := range rows {
+		lines = append(lines, fmt.Sprintf("%d %s %s %s: %s", r.Line, r.Date.Format("2006-01-02"), r.Duration, r.Project, r.Description))
+	}
+	return lines
+}